@@ -0,0 +1,138 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// WeightPolicyEqual publishes ready endpoints only, every instance with the same weight
+	WeightPolicyEqual = "Equal"
+	// WeightPolicyDrainNotReady also publishes not ready endpoints, with weight 0
+	WeightPolicyDrainNotReady = "DrainNotReady"
+
+	// DeletionPolicyDelete deletes the pools in BFE when the binding is deleted
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain keeps the pools in BFE when the binding is deleted
+	DeletionPolicyRetain = "Retain"
+	// DeletionPolicyClear keeps the pools in BFE but removes their instances when the binding is deleted
+	DeletionPolicyClear = "Clear"
+
+	DefaultInstanceWeight = 1
+)
+
+// PortBinding maps a service port to a BFE instance pool
+type PortBinding struct {
+	// Name of the service port
+	Name string `json:"name"`
+
+	// PoolName is the BFE instance pool name, prefixed with "<product>." if not yet.
+	// Default is generated from namespace, service name, port name and cluster name.
+	// +optional
+	PoolName string `json:"poolName,omitempty"`
+}
+
+// WeightPolicy decides the weight of published instances
+type WeightPolicy struct {
+	// Type is Equal or DrainNotReady, default Equal
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Weight of every ready instance, 0 ~ 100, default 1
+	// +optional
+	Weight *int64 `json:"weight,omitempty"`
+}
+
+// BfeServiceBindingSpec defines how a Service is published to BFE
+type BfeServiceBindingSpec struct {
+	// ServiceName is the name of the Service in the same namespace
+	ServiceName string `json:"serviceName"`
+
+	// Products are the BFE products the pools are created in
+	Products []string `json:"products"`
+
+	// Ports to publish, default every named port of the service
+	// +optional
+	Ports []PortBinding `json:"ports,omitempty"`
+
+	// +optional
+	WeightPolicy WeightPolicy `json:"weightPolicy,omitempty"`
+
+	// DeletionPolicy is Delete, Retain or Clear, default Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// BoundPoolStatus is the state of one pool in BFE
+type BoundPoolStatus struct {
+	Product   string `json:"product"`
+	Name      string `json:"name"`
+	Port      string `json:"port"`
+	Instances int    `json:"instances"`
+}
+
+// BfeServiceBindingStatus defines the observed state of BfeServiceBinding
+type BfeServiceBindingStatus struct {
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Pools in BFE managed by the binding
+	// +optional
+	Pools []BoundPoolStatus `json:"pools,omitempty"`
+
+	// LastResyncAt is the last processed value of annotation k8s.bfenetworks.com/resync-at
+	// +optional
+	LastResyncAt string `json:"lastResyncAt,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// BfeServiceBinding declares the BFE pools of a Service
+type BfeServiceBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BfeServiceBindingSpec   `json:"spec,omitempty"`
+	Status BfeServiceBindingStatus `json:"status,omitempty"`
+}
+
+// BfeServiceBindingList contains a list of BfeServiceBinding
+type BfeServiceBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BfeServiceBinding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BfeServiceBinding{}, &BfeServiceBindingList{})
+}
+
+// GetDeletionPolicy returns the deletion policy with default applied
+func (b *BfeServiceBinding) GetDeletionPolicy() string {
+	if b.Spec.DeletionPolicy == "" {
+		return DeletionPolicyDelete
+	}
+	return b.Spec.DeletionPolicy
+}
+
+// GetWeight returns the weight of ready instances with default applied
+func (b *BfeServiceBinding) GetWeight() int64 {
+	if b.Spec.WeightPolicy.Weight == nil {
+		return DefaultInstanceWeight
+	}
+	return *b.Spec.WeightPolicy.Weight
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the receiver into out
+func (in *WeightPolicy) DeepCopyInto(out *WeightPolicy) {
+	*out = *in
+	if in.Weight != nil {
+		out.Weight = new(int64)
+		*out.Weight = *in.Weight
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *BfeServiceBindingSpec) DeepCopyInto(out *BfeServiceBindingSpec) {
+	*out = *in
+	if in.Products != nil {
+		out.Products = make([]string, len(in.Products))
+		copy(out.Products, in.Products)
+	}
+	if in.Ports != nil {
+		out.Ports = make([]PortBinding, len(in.Ports))
+		copy(out.Ports, in.Ports)
+	}
+	in.WeightPolicy.DeepCopyInto(&out.WeightPolicy)
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *BfeServiceBindingStatus) DeepCopyInto(out *BfeServiceBindingStatus) {
+	*out = *in
+	if in.Pools != nil {
+		out.Pools = make([]BoundPoolStatus, len(in.Pools))
+		copy(out.Pools, in.Pools)
+	}
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *BfeServiceBinding) DeepCopyInto(out *BfeServiceBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy creates a new BfeServiceBinding
+func (in *BfeServiceBinding) DeepCopy() *BfeServiceBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(BfeServiceBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *BfeServiceBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *BfeServiceBindingList) DeepCopyInto(out *BfeServiceBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]BfeServiceBinding, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy creates a new BfeServiceBindingList
+func (in *BfeServiceBindingList) DeepCopy() *BfeServiceBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(BfeServiceBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (in *BfeServiceBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
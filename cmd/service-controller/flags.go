@@ -34,25 +34,63 @@ func initFlags() {
 	flag.BoolVar(&showVersion, "version", false, "Show version of bfe-ingress-controller.")
 	flag.BoolVar(&showVersion, "v", false, "Show version of bfe-ingress-controller.")
 
-	flag.StringVar(&opts.ExternalLB.ApiServerAddr, "bfe-api-addr", opts.ExternalLB.ApiServerAddr, "Address of ALB api server")
-	flag.StringVar(&opts.ExternalLB.Token, "bfe-api-token", opts.ExternalLB.Token, "access token of ALB api server")
+	flag.StringVar(&opts.ExternalLB.ApiServerAddr, "bfe-api-addr", opts.ExternalLB.ApiServerAddr, "Address of ALB api server, delimited by ',' for multiple addresses")
+	flag.IntVar(&opts.ExternalLB.Retries, "bfe-api-retries", opts.ExternalLB.Retries, "max retries of an ALB api call failed by network error, 5xx or 429, creating a pool is retried only if it does not exist")
+	flag.IntVar(&opts.ExternalLB.RetryBaseDelay, "bfe-api-retry-base-delay", opts.ExternalLB.RetryBaseDelay, "delay before the first retry of an ALB api call, doubled for each retry, in millisecond")
+	flag.IntVar(&opts.ExternalLB.RetryMaxDelay, "bfe-api-retry-max-delay", opts.ExternalLB.RetryMaxDelay, "max delay between retries of an ALB api call, in millisecond")
+	flag.IntVar(&opts.ExternalLB.CircuitThreshold, "bfe-api-circuit-threshold", opts.ExternalLB.CircuitThreshold, "consecutive failed ALB api calls to open the circuit breaker, which fails calls fast(0, means disable)")
+	flag.IntVar(&opts.ExternalLB.CircuitOpenDuration, "bfe-api-circuit-open-duration", opts.ExternalLB.CircuitOpenDuration, "time before an open circuit breaker lets one ALB api call through to probe, in millisecond")
+	flag.Float64Var(&opts.ExternalLB.QPS, "bfe-api-qps", opts.ExternalLB.QPS, "max rate of ALB api requests per second(0, means no limit)")
+	flag.IntVar(&opts.ExternalLB.Burst, "bfe-api-burst", opts.ExternalLB.Burst, "max burst of ALB api requests over bfe-api-qps")
+	flag.IntVar(&opts.ExternalLB.MaxInFlight, "bfe-api-max-in-flight", opts.ExternalLB.MaxInFlight, "max concurrent ALB api requests, queued requests are served in turn across products(0, means no limit)")
+	flag.StringVar(&opts.ExternalLB.Proxy, "bfe-api-proxy", opts.ExternalLB.Proxy, "proxy to ALB api server, none: connect directly; empty: from HTTP_PROXY, HTTPS_PROXY and NO_PROXY env")
+	flag.StringVar(&opts.ExternalLB.TLSCAFile, "bfe-api-tls-ca-file", opts.ExternalLB.TLSCAFile, "CA bundle to verify https ALB api server, system CAs are used if empty")
+	flag.StringVar(&opts.ExternalLB.TLSCertFile, "bfe-api-tls-cert-file", opts.ExternalLB.TLSCertFile, "client certificate for mTLS to ALB api server")
+	flag.StringVar(&opts.ExternalLB.TLSKeyFile, "bfe-api-tls-key-file", opts.ExternalLB.TLSKeyFile, "client private key for mTLS to ALB api server")
+	flag.StringVar(&opts.ExternalLB.TLSServerName, "bfe-api-tls-server-name", opts.ExternalLB.TLSServerName, "server name to verify the certificate of ALB api server, the host of address if empty")
+	flag.StringVar(&opts.ExternalLB.TLSMinVersion, "bfe-api-tls-min-version", opts.ExternalLB.TLSMinVersion, "min TLS version to ALB api server, 1.0, 1.1, 1.2 or 1.3")
+	flag.StringVar(&opts.ExternalLB.BalanceMode, "bfe-api-balance-mode", opts.ExternalLB.BalanceMode, "how to use multiple ALB api server addresses, failover: prefer the first healthy one; round-robin: rotate among healthy ones")
+	flag.IntVar(&opts.ExternalLB.UnhealthyCooldown, "bfe-api-unhealthy-cooldown", opts.ExternalLB.UnhealthyCooldown, "time before an ALB api server address failed to connect is preferred again, in millisecond")
+	flag.StringVar(&opts.ExternalLB.TokenFile, "bfe-api-token-file", opts.ExternalLB.TokenFile, "file of the access token of ALB api server, reloaded on change")
+	flag.StringVar(&opts.ExternalLB.TokenSecret, "bfe-api-token-secret", opts.ExternalLB.TokenSecret, "secret of the access token of ALB api server, <namespace>/<name>[:<key>](key defaults to token), reloaded on change")
+	flag.StringVar(&opts.ExternalLB.Token, "bfe-api-token", opts.ExternalLB.Token, "access token of ALB api server, visible in process list, prefer bfe-api-token-file or bfe-api-token-secret")
+	flag.IntVar(&opts.ExternalLB.Timeout, "bfe-api-timeout", opts.ExternalLB.Timeout, "default timeout of ALB api calls, in millisecond")
+	flag.IntVar(&opts.ExternalLB.ReadTimeout, "bfe-api-read-timeout", opts.ExternalLB.ReadTimeout, "timeout of ALB api calls reading pools, in millisecond(0, means bfe-api-timeout)")
+	flag.IntVar(&opts.ExternalLB.WriteTimeout, "bfe-api-write-timeout", opts.ExternalLB.WriteTimeout, "timeout of ALB api calls creating or updating pools, in millisecond(0, means bfe-api-timeout)")
+	flag.IntVar(&opts.ExternalLB.DeleteTimeout, "bfe-api-delete-timeout", opts.ExternalLB.DeleteTimeout, "timeout of ALB api calls deleting pools, in millisecond(0, means bfe-api-timeout)")
 
 	flag.StringVar(&opts.ClusterName, "k8s-cluster-name", opts.ClusterName, "k8s cluster name")
+	flag.StringVar(&opts.ControllerID, "controller-id", opts.ControllerID, "id of this controller, written on BFE pool instances to mark their owner")
+	flag.BoolVar(&opts.SharedPool, "shared-pool", false, "publish a service to one pool shared by all clusters, each cluster only manages its own instances in it")
+	flag.IntVar(&opts.SharedPoolResyncS, "shared-pool-resync-sec", opts.SharedPoolResyncS, "interval of re-checking shared pools, to restore the instances overwritten by other clusters, in second(0, means never)")
+	flag.IntVar(&opts.ClusterWeight, "cluster-weight", opts.ClusterWeight, "weight factor applied to every instance published by this cluster, 0 drains the cluster(instance weight is capped at 100)")
+	flag.BoolVar(&opts.AdoptUnownedPools, "adopt-unowned-pools", false, "take over the BFE pools not owned by the service, instead of refusing to modify or delete them")
 
 	flag.IntVar(&opts.RetryIntervalUnitForErrS, "retry-interval-unit-sec", -1, "retry interval second(<=0, means use default retry interval)")
 	flag.BoolVar(&opts.ForceRmFinalizer, "force-rm-finalizer", false, "will remove finalizer even deleting failed")
+	flag.StringVar(&opts.DeletionPolicy, "deletion-policy", opts.DeletionPolicy, "default action on BFE pools when service is deleted, delete, retain or clear(keep pool but remove instances)")
 
 	flag.StringVar(&opts.Namespaces, "namespace", opts.Namespaces, "Namespaces to watch, delimited by ',', '*' for all.")
 	flag.StringVar(&opts.Namespaces, "n", opts.Namespaces, "Namespaces to watch, delimited by ',', '*' for all.")
+	flag.StringVar(&opts.ResultMode, "result-mode", opts.ResultMode, "where to record sync result, condition: service status conditions; configmap: <svc>.result configmap(legacy); both")
+	flag.BoolVar(&opts.EnableServiceBinding, "enable-service-binding", false, "handle BfeServiceBinding resources, the CRD must be installed")
 	flag.BoolVar(&opts.SkipNilSvcDelete, "skip-nil-svc-delete", true, "is skip nil service delete")
 
 	flag.StringVar(&opts.MetricsAddr, "metrics-bind-address", opts.MetricsAddr, "The address the metric endpoint binds to.")
 	flag.StringVar(&opts.HealthProbeAddr, "health-probe-bind-address", opts.HealthProbeAddr, "The address the probe endpoint binds to.")
 	flag.StringVar(&opts.ReadinessEndpointName, "readiness-endpoint-name", opts.ReadinessEndpointName, "Readiness probe endpoint name")
 	flag.StringVar(&opts.LivenessEndpointName, "liveness-endpoint-name", opts.LivenessEndpointName, "Liveness probe endpoint name")
+	flag.StringVar(&opts.StatuszEndpointName, "statusz-endpoint-name", opts.StatuszEndpointName, "Status detail page endpoint name, served by the metrics endpoint")
 	flag.StringVar(&opts.PProfAddr, "pprof-address", opts.PProfAddr, "The address to enable pprof, for example :6060.")
-	flag.IntVar(&opts.UnreadyDuration, "unready-duration", opts.UnreadyDuration, "keep unready when starting for a period of time, in second")
+	flag.IntVar(&opts.UnreadyDuration, "unready-duration", opts.UnreadyDuration, "max time to keep unready when starting while waiting for the initial sync of services, in second")
 	flag.IntVar(&opts.ReconcileRate, "reconcile-rate", opts.ReconcileRate, "Set rate limit in processing reconcile request (per second).")
 	flag.IntVar(&opts.ReconcileBucket, "reconcile-bucket", opts.ReconcileBucket, "Set ratelimiter bucket size for reconcile request.")
+	flag.IntVar(&opts.EndpointsDebounceMs, "endpoints-debounce-ms", opts.EndpointsDebounceMs, "merge endpoints changes of a service within the window into one pool update, in millisecond(0, means disable)")
+	flag.IntVar(&opts.EndpointsMaxDelayMs, "endpoints-max-delay-ms", opts.EndpointsMaxDelayMs, "max delay of propagating endpoints changes when debouncing, in millisecond")
+	flag.Float64Var(&opts.MaxShrinkRatio, "max-shrink-ratio", opts.MaxShrinkRatio, "max ratio of instances removed from a pool in one update, larger is held(0, means no limit)")
+	flag.IntVar(&opts.MinPoolInstances, "min-pool-instances", opts.MinPoolInstances, "update shrinking a pool below this number of instances is held(0, means no limit)")
+	flag.IntVar(&opts.ApiProbeInterval, "bfe-api-probe-interval", opts.ApiProbeInterval, "interval of probing ALB api server, in second(<=0, means disable probe)")
+	flag.StringVar(&opts.ApiProbeMode, "bfe-api-probe-mode", opts.ApiProbeMode, "fail: unready when ALB api server unreachable; report: only show in status page")
+	flag.StringVar(&opts.ApiCircuitMode, "bfe-api-circuit-mode", opts.ApiCircuitMode, "fail: unready when ALB api circuit breaker opens; report: only show in status page")
 
 }
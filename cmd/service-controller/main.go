@@ -27,6 +27,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
 	"github.com/bfenetworks/service-controller/internal/controllers"
 	"github.com/bfenetworks/service-controller/internal/option"
 )
@@ -38,6 +39,7 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	initFlags()
 }
 
@@ -16,11 +16,14 @@ package openapi
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
-	"github.com/bfenetworks/service-controller/internal/alb/apis/product_pool"
+	"github.com/bfenetworks/service-controller/internal/metrics"
 	"github.com/bfenetworks/service-controller/internal/option/externalLB"
 	util "github.com/bfenetworks/service-controller/internal/util"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -31,35 +34,109 @@ type ProductPoolname struct {
 
 type ProductPoolnameList []ProductPoolname
 
+// PoolStatus is the result of ensuring one pool
+type PoolStatus struct {
+	Name      string
+	Port      string
+	Instances int // number of instances with weight > 0 written to the pool
+
+	ShrinkApproved bool // a shrink held by the guard was written as approved
+}
+
+// PoolBinding describes how a service port is published to a pool
+type PoolBinding struct {
+	PortName string
+	PoolName string
+
+	Weight          int64
+	IncludeNotReady bool // publish not ready endpoints with weight 0
+	Adopt           bool // take over the pool if it is not managed by any controller
+}
+
+// EnsureOptions are the options of one ensuring call
+type EnsureOptions struct {
+	Force bool // update the pool even if its instances are unchanged
+
+	// guard against mass instance removal, see checkShrink
+	MaxShrinkRatio float64
+	MinInstances   int
+	ApproveShrink  bool
+
+	Ownership
+}
+
+// ShrinkError is returned when an update is held because it removes too many instances of a pool
+type ShrinkError struct {
+	Pool    string
+	Current int
+	Desired int
+	Reason  string
+}
+
+func (e *ShrinkError) Error() string {
+	return fmt.Sprintf("update of pool %s held, shrinking from %d to %d instances %s", e.Pool, e.Current, e.Desired, e.Reason)
+}
+
 type AlbProvider struct {
 	options *externalLB.Options
 	client  *OpenApiClient
 }
 
-func NewAlbProvider(opts *externalLB.Options) *AlbProvider {
+func NewAlbProvider(opts *externalLB.Options) (*AlbProvider, error) {
+	client, err := NewOpenApiClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create alb api client: %s", err)
+	}
+
 	return &AlbProvider{
 		options: opts,
-		client: NewOpenApiClient(
-			opts.ApiServerAddr,
-			opts.Token,
-			opts.Timeout),
+		client:  client,
+	}, nil
+}
+
+// SetCircuitListener sets the listener of the circuit breaker of api server
+func (p *AlbProvider) SetCircuitListener(listener CircuitListener) {
+	p.client.breaker.setListener(listener)
+}
+
+// SetToken replaces the token of api server, used when the token is not read from file
+func (p *AlbProvider) SetToken(token string) {
+	p.client.token.set(token)
+}
+
+// ReloadTokenFile re-reads the token file of api server if it changed, or if force.
+// It returns whether the token is reloaded, false if the token is not read from file.
+func (p *AlbProvider) ReloadTokenFile(force bool) (bool, error) {
+	if p.options.TokenFile == "" {
+		return false, nil
 	}
+	return p.client.token.reloadFile(force)
+}
+
+// TokenRejected returns the channel notified when api server rejects the token, so that it is
+// reloaded without waiting for the next check
+func (p *AlbProvider) TokenRejected() <-chan struct{} {
+	return p.client.token.rejected
 }
 
-func getInstances(ep *v1.Endpoints, portName string) []*product_pool.Instance {
+// CheckApiServer checks whether the api server is reachable with the configured token
+func (p *AlbProvider) CheckApiServer(ctx context.Context) error {
+	return p.client.Ping(ctx)
+}
+
+func getInstances(ep *v1.Endpoints, binding PoolBinding, tags map[string]string) []*product_pool.Instance {
 	instances := make([]*product_pool.Instance, 0)
 
 	for _, subset := range ep.Subsets {
 		for _, p := range subset.Ports {
-			if p.Name == portName {
+			if p.Name == binding.PortName {
 				for _, addr := range subset.Addresses {
-					instances = append(instances, &product_pool.Instance{
-						Hostname: addr.IP, //addr.Hostname,
-						IP:       addr.IP,
-						Weight:   1,
-						Ports:    map[string]int{"Default": int(p.Port)},
-						Tags:     map[string]string{"key": "value"},
-					})
+					instances = append(instances, newInstance(addr, p.Port, binding.Weight, tags))
+				}
+				if binding.IncludeNotReady {
+					for _, addr := range subset.NotReadyAddresses {
+						instances = append(instances, newInstance(addr, p.Port, 0, tags))
+					}
 				}
 				break
 			}
@@ -69,76 +146,381 @@ func getInstances(ep *v1.Endpoints, portName string) []*product_pool.Instance {
 	return instances
 }
 
-func (p *AlbProvider) EnsureProductPool(ctx context.Context, product string, service *v1.Service,
-	ep *v1.Endpoints, clusterName string) ([]string, error) {
+func newInstance(addr v1.EndpointAddress, port int32, weight int64, tags map[string]string) *product_pool.Instance {
+	return &product_pool.Instance{
+		Hostname: addr.IP, //addr.Hostname,
+		IP:       addr.IP,
+		Weight:   weight,
+		Ports:    map[string]int{"Default": int(port)},
+		Tags:     tags,
+	}
+}
 
-	namespace := service.GetNamespace()
-	name := service.GetName()
-	poolNames := make([]string, 0, len(service.Spec.Ports))
+// checkShrink checks whether shrinking a pool from current to desired instances is allowed
+func checkShrink(pool string, current, desired int, opts EnsureOptions) error {
+	if desired >= current {
+		return nil
+	}
+
+	if desired < opts.MinInstances {
+		return &ShrinkError{Pool: pool, Current: current, Desired: desired,
+			Reason: fmt.Sprintf("below the minimum %d", opts.MinInstances)}
+	}
+	if opts.MaxShrinkRatio > 0 && float64(current-desired)/float64(current) > opts.MaxShrinkRatio {
+		return &ShrinkError{Pool: pool, Current: current, Desired: desired,
+			Reason: fmt.Sprintf("exceeds the max shrink ratio %.2f", opts.MaxShrinkRatio)}
+	}
+	return nil
+}
 
+// sameInstances checks whether two instance lists are the same, regardless of order
+func sameInstances(a, b []*product_pool.Instance) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	keys := make(map[string]int, len(a))
+	for _, ins := range a {
+		keys[instanceKey(ins)]++
+	}
+	for _, ins := range b {
+		key := instanceKey(ins)
+		if keys[key] == 0 {
+			return false
+		}
+		keys[key]--
+	}
+	return true
+}
+
+func instanceKey(ins *product_pool.Instance) string {
+	// json encoding of map is sorted by key
+	ports, _ := json.Marshal(ins.Ports)
+	tags, _ := json.Marshal(ins.Tags)
+	return fmt.Sprintf("%s|%s|%d|%s|%s", ins.Hostname, ins.IP, ins.Weight, ports, tags)
+}
+
+func countWeighted(instances []*product_pool.Instance) int {
+	n := 0
+	for _, ins := range instances {
+		if ins.Weight > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// DefaultPoolBindings binds every named port of the service to the default pool name
+func DefaultPoolBindings(product string, service *v1.Service, clusterName string) []PoolBinding {
+	bindings := make([]PoolBinding, 0, len(service.Spec.Ports))
 	for _, port := range service.Spec.Ports {
-		portName := port.Name
-		if portName == "" {
+		if port.Name == "" {
 			continue
 		}
+		bindings = append(bindings, PoolBinding{
+			PortName: port.Name,
+			PoolName: PoolName(product, service.GetNamespace(), service.GetName(), port.Name, clusterName),
+			Weight:   1,
+		})
+	}
+	return bindings
+}
+
+// PlanProductPoolByBindings returns the pools EnsureProductPoolByBindings would write,
+// without calling the api server
+func PlanProductPoolByBindings(ep *v1.Endpoints, bindings []PoolBinding) []PoolStatus {
+	pools := make([]PoolStatus, 0, len(bindings))
+	for _, binding := range bindings {
+		pools = append(pools, PoolStatus{
+			Name:      binding.PoolName,
+			Port:      binding.PortName,
+			Instances: countWeighted(getInstances(ep, binding, nil)),
+		})
+	}
+	return pools
+}
+
+// EnsureProductPoolByBindings creates or updates the pool of every binding with the endpoints
+func (p *AlbProvider) EnsureProductPoolByBindings(ctx context.Context, product string, ep *v1.Endpoints,
+	bindings []PoolBinding, opts EnsureOptions) ([]PoolStatus, error) {
+	pools := make([]PoolStatus, 0, len(bindings))
+	tags := opts.Owner.Tags()
 
-		pool := poolName(product, namespace, name, portName, clusterName)
-		servers := getInstances(ep, portName)
+	for _, binding := range bindings {
+		status, err := p.ensurePool(ctx, product, binding, getInstances(ep, binding, tags), opts)
+		if err != nil {
+			return pools, err
+		}
+		pools = append(pools, status)
+	}
+	return pools, nil
+}
+
+// ensurePool writes servers to the pool of binding. In shared mode, the instances of the same service
+// in other clusters are kept and only the instances of this cluster are replaced.
+func (p *AlbProvider) ensurePool(ctx context.Context, product string, binding PoolBinding, servers []*product_pool.Instance,
+	opts EnsureOptions) (PoolStatus, error) {
+	pool := binding.PoolName
+	status := PoolStatus{Name: pool, Port: binding.PortName, Instances: countWeighted(servers)}
+
+	if len(servers) == 0 && !opts.Shared {
+		util.HdlLogger.Info("product instance is empty, skip bfe api operation but record", "poolname", pool)
+		return status, nil
+	}
+
+	rsp, _, err := p.client.GetProductPool(ctx, product, pool)
+	if err != nil && !IsNotFound(err) {
+		// can not tell whether the pool exists
+		util.HdlLogger.Error(err, "failed to get product pool", "poolname", pool)
+		return status, err
+	}
+	if err != nil {
 		if len(servers) == 0 {
 			util.HdlLogger.Info("product instance is empty, skip bfe api operation but record", "poolname", pool)
-			poolNames = append(poolNames, pool)
-			continue
+			return status, nil
 		}
 
+		// pool doesn't exist yet. create it
 		param := &product_pool.UpsertParam{
 			Name:      &pool,
 			Instances: servers,
 		}
+		if _, _, err := p.client.CreateProductPool(ctx, product, param); err != nil {
+			// on conflict, the pool is created by others meanwhile, and will be updated by the retry
+			util.HdlLogger.Error(err, "failed to create product pool", "poolname", pool, "req", param, "conflict", IsConflict(err))
+			return status, err
+		}
+		util.HdlLogger.Info("create product pool succ", "poolname", pool, "req", param)
+		return status, nil
+	}
+
+	if err := checkOwnership(pool, rsp.Instances, opts.Ownership, binding.Adopt); err != nil {
+		util.HdlLogger.Info("product pool not owned, refuse to update", "poolname", pool, "err", err.Error())
+		recordNotOwned(product, "update", err)
+		return status, err
+	}
+
+	current, desired := rsp.Instances, servers
+	if opts.Shared {
+		var peers []*product_pool.Instance
+		current, peers = splitInstances(rsp.Instances, opts.Owner)
+		desired = append(peers, servers...)
+	}
+
+	if !opts.Force && sameInstances(rsp.Instances, desired) {
+		util.HdlLogger.Info("product pool unchanged, skip update", "poolname", pool)
+		return status, nil
+	}
+	if err := checkShrink(pool, len(current), len(servers), opts); err != nil {
+		if !opts.ApproveShrink {
+			util.HdlLogger.Info("product pool shrinks too much, hold update", "poolname", pool, "err", err.Error())
+			metrics.PoolShrinkHeld.WithLabelValues(product).Inc()
+			return status, err
+		}
+		util.HdlLogger.Info("product pool shrink approved", "poolname", pool, "err", err.Error())
+		status.ShrinkApproved = true
+	}
 
-		_, _, err := p.client.GetProductPool(product, pool)
+	// update it
+	param := &product_pool.UpsertParam{
+		Name:      &pool,
+		Instances: desired,
+	}
+	if _, _, err := p.client.UpdateProductPool(ctx, product, param); err != nil {
+		util.HdlLogger.Error(err, "failed to update product pool", "poolname", pool, "req", param)
+		return status, err
+	}
+	util.HdlLogger.Info("update product pool succ", "poolname", pool, "req", param)
+
+	if opts.Shared {
+		return status, p.verifySharedPool(ctx, product, pool, servers)
+	}
+	return status, nil
+}
+
+// verifySharedPool checks the servers written are still in the pool, as controllers of other clusters
+// may update the shared pool concurrently and overwrite them. The last writer wins, an overwrite after
+// this check is only repaired by the periodic resync of shared pools.
+func (p *AlbProvider) verifySharedPool(ctx context.Context, product, pool string, servers []*product_pool.Instance) error {
+	rsp, _, err := p.client.GetProductPool(ctx, product, pool)
+	if err != nil {
+		return fmt.Errorf("fail to verify shared pool %s: %s", pool, err)
+	}
+
+	keys := make(map[string]bool, len(rsp.Instances))
+	for _, ins := range rsp.Instances {
+		keys[instanceKey(ins)] = true
+	}
+	for _, ins := range servers {
+		if !keys[instanceKey(ins)] {
+			metrics.SharedPoolConflicts.WithLabelValues(product).Inc()
+			return fmt.Errorf("instances of shared pool %s overwritten by concurrent update, will retry", pool)
+		}
+	}
+	return nil
+}
+
+// DeleteProductPoolByList deletes the pools, the ones not owned are left in BFE and regarded as deleted.
+// In shared mode, only the instances of this cluster are removed, and a pool is deleted when no
+// instance of other clusters is left.
+func (p *AlbProvider) DeleteProductPoolByList(ctx context.Context, poollist ProductPoolnameList,
+	ownership Ownership) (ProductPoolnameList, error) {
+	poolNames := make(ProductPoolnameList, 0, len(poollist))
+
+	for _, pool := range poollist {
+		rsp, _, err := p.client.GetProductPool(ctx, pool.Product, pool.Poolname)
+		if IsNotFound(err) || IsProductNotFound(err) {
+			util.HdlLogger.Info("delete product pool, skip nonexistent pool", "poolname", pool.Poolname)
+			poolNames = append(poolNames, pool)
+			continue
+		}
 		if err != nil {
-			// pool doesn't exist yet. create it
-			_, _, err := p.client.CreateProductPool(product, param)
-			if err != nil {
-				util.HdlLogger.Error(err, "failed to create product pool", "poolname", pool, "req", param)
-				return poolNames, err
-			} else {
-				util.HdlLogger.Info("create product pool succ", "poolname", pool, "req", param)
+			util.HdlLogger.Error(err, "get product pool before delete", "poolname", pool.Poolname)
+			return poolNames, err
+		}
+		if err := checkOwnership(pool.Poolname, rsp.Instances, ownership, false); err != nil {
+			// leave it in BFE
+			util.HdlLogger.Info("product pool not owned, refuse to delete", "poolname", pool.Poolname, "err", err.Error())
+			recordNotOwned(pool.Product, "delete", err)
+			poolNames = append(poolNames, pool)
+			continue
+		}
+
+		if ownership.Shared {
+			if _, peers := splitInstances(rsp.Instances, ownership.Owner); len(peers) > 0 {
+				if e := p.updateInstances(ctx, pool, peers); e != nil {
+					util.HdlLogger.Error(e, "remove instances from shared product pool", "poolname", pool.Poolname)
+					return poolNames, e
+				}
+				util.HdlLogger.Info("remove instances from shared product pool succ", "poolname", pool.Poolname)
 				poolNames = append(poolNames, pool)
+				continue
 			}
+		}
+
+		e := p.client.DeleteProductPool(ctx, pool.Product, pool.Poolname)
+		if e == nil {
+			util.HdlLogger.Info("delete product pool succ", "poolname", pool.Poolname)
+			poolNames = append(poolNames, pool)
 		} else {
-			// update it
-			_, _, err := p.client.UpdateProductPool(product, param)
-			if err != nil {
-				util.HdlLogger.Error(err, "failed to update product pool", "poolname", pool, "req", param)
-				return poolNames, err
-			} else {
-				util.HdlLogger.Info("update product pool succ", "poolname", pool, "req", param)
-				poolNames = append(poolNames, pool)
-			}
+			util.HdlLogger.Error(e, "delete product pool", "poolname", pool.Poolname)
+			return poolNames, e
 		}
 	}
+
 	return poolNames, nil
 }
 
-func (p *AlbProvider) DeleteProductPoolByList(ctx context.Context, poollist ProductPoolnameList) (ProductPoolnameList, error) {
+// ClearProductPoolByList keeps the pools in BFE but removes all instances of them, or only the instances
+// of this cluster in shared mode. The ones not owned are left untouched and regarded as cleared.
+func (p *AlbProvider) ClearProductPoolByList(ctx context.Context, poollist ProductPoolnameList,
+	ownership Ownership) (ProductPoolnameList, error) {
 	poolNames := make(ProductPoolnameList, 0, len(poollist))
 
 	for _, pool := range poollist {
-		e := p.client.DeleteProductPool(pool.Product, pool.Poolname)
-		if e == nil {
-			util.HdlLogger.Info("delete product pool succ", "poolname", pool.Poolname)
+		rsp, _, err := p.client.GetProductPool(ctx, pool.Product, pool.Poolname)
+		if IsNotFound(err) || IsProductNotFound(err) {
+			// pool doesn't exist, nothing to clear
+			util.HdlLogger.Info("clear product pool, skip nonexistent pool", "poolname", pool.Poolname)
 			poolNames = append(poolNames, pool)
-		} else {
-			util.HdlLogger.Error(e, "delete product pool", "poolname", pool.Poolname)
+			continue
+		}
+		if err != nil {
+			util.HdlLogger.Error(err, "get product pool before clear", "poolname", pool.Poolname)
+			return poolNames, err
+		}
+		if err := checkOwnership(pool.Poolname, rsp.Instances, ownership, false); err != nil {
+			util.HdlLogger.Info("product pool not owned, refuse to clear", "poolname", pool.Poolname, "err", err.Error())
+			recordNotOwned(pool.Product, "clear", err)
+			poolNames = append(poolNames, pool)
+			continue
+		}
+
+		instances := []*product_pool.Instance{}
+		if ownership.Shared {
+			_, instances = splitInstances(rsp.Instances, ownership.Owner)
+		}
+		if e := p.updateInstances(ctx, pool, instances); e != nil {
+			util.HdlLogger.Error(e, "clear product pool", "poolname", pool.Poolname)
 			return poolNames, e
 		}
+		util.HdlLogger.Info("clear product pool succ", "poolname", pool.Poolname)
+		poolNames = append(poolNames, pool)
 	}
 
 	return poolNames, nil
 }
 
-func poolName(product string, namespace string, name string, portName string, clusterName string) string {
+// ReleaseProductPoolByList leaves the pools in BFE but no longer owned, by replacing the ownership tags
+// of their instances, so they can be adopted again. The instances of other clusters are left as they are
+// in shared mode. The pools not owned are left untouched and regarded as released.
+func (p *AlbProvider) ReleaseProductPoolByList(ctx context.Context, poollist ProductPoolnameList,
+	ownership Ownership) (ProductPoolnameList, error) {
+	poolNames := make(ProductPoolnameList, 0, len(poollist))
+
+	for _, pool := range poollist {
+		rsp, _, err := p.client.GetProductPool(ctx, pool.Product, pool.Poolname)
+		if IsNotFound(err) || IsProductNotFound(err) {
+			util.HdlLogger.Info("release product pool, skip nonexistent pool", "poolname", pool.Poolname)
+			poolNames = append(poolNames, pool)
+			continue
+		}
+		if err != nil {
+			util.HdlLogger.Error(err, "get product pool before release", "poolname", pool.Poolname)
+			return poolNames, err
+		}
+		if err := checkOwnership(pool.Poolname, rsp.Instances, ownership, false); err != nil {
+			util.HdlLogger.Info("product pool not owned, skip release", "poolname", pool.Poolname, "err", err.Error())
+			recordNotOwned(pool.Product, "release", err)
+			poolNames = append(poolNames, pool)
+			continue
+		}
+
+		changed := false
+		instances := make([]*product_pool.Instance, 0, len(rsp.Instances))
+		for _, ins := range rsp.Instances {
+			if ownership.Owner.owns(ins.Tags) || isLegacyTags(ins.Tags) {
+				released := *ins
+				released.Tags = releasedTags(ownership.Owner.ControllerID)
+				ins = &released
+				changed = true
+			}
+			instances = append(instances, ins)
+		}
+		if changed {
+			if e := p.updateInstances(ctx, pool, instances); e != nil {
+				util.HdlLogger.Error(e, "release product pool", "poolname", pool.Poolname)
+				return poolNames, e
+			}
+		}
+		util.HdlLogger.Info("release product pool succ", "poolname", pool.Poolname)
+		poolNames = append(poolNames, pool)
+	}
+
+	return poolNames, nil
+}
+
+// recordNotOwned counts a pool operation refused as the pool is not owned
+func recordNotOwned(product, op string, err error) {
+	metrics.PoolNotOwned.WithLabelValues(product, op).Inc()
+	var oerr *NotOwnedError
+	if errors.As(err, &oerr) && oerr.Collision {
+		metrics.PoolCollisions.WithLabelValues(product).Inc()
+	}
+}
+
+func (p *AlbProvider) updateInstances(ctx context.Context, pool ProductPoolname, instances []*product_pool.Instance) error {
+	name := pool.Poolname
+	param := &product_pool.UpsertParam{
+		Name:      &name,
+		Instances: instances,
+	}
+	_, _, err := p.client.UpdateProductPool(ctx, pool.Product, param)
+	return err
+}
+
+// PoolName generates the default pool name of a service port
+func PoolName(product string, namespace string, name string, portName string, clusterName string) string {
 	if clusterName == "" {
 		return fmt.Sprintf("%s.k8s_%s_%s_%s", product, namespace, name, portName)
 	}
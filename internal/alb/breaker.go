@@ -0,0 +1,164 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("state-%d", int(s))
+}
+
+// CircuitListener is called when the circuit breaker opens or closes
+type CircuitListener func(open bool, reason string)
+
+// circuitBreaker fails requests fast while the api server is down. It opens after threshold
+// consecutive transient failures, and after openDuration lets one request through as a probe,
+// which closes it on success or opens it again on failure.
+type circuitBreaker struct {
+	lock         sync.Mutex
+	state        circuitState
+	failures     int
+	lastErr      string
+	openedAt     time.Time
+	probing      bool
+	threshold    int // 0 means disabled
+	openDuration time.Duration
+	listener     CircuitListener
+}
+
+func newCircuitBreaker(threshold int, openDuration time.Duration) *circuitBreaker {
+	metrics.ApiCircuitState.Set(float64(circuitClosed))
+	return &circuitBreaker{
+		threshold:    threshold,
+		openDuration: openDuration,
+	}
+}
+
+func (b *circuitBreaker) setListener(listener CircuitListener) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.listener = listener
+}
+
+// allow returns an error if the request should fail fast
+func (b *circuitBreaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.openDuration {
+			return b.openError()
+		}
+		// let this request probe the api server
+		b.setState(circuitHalfOpen)
+		b.probing = true
+	case circuitHalfOpen:
+		if b.probing {
+			return b.openError()
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record records the result of an allowed request, err is nil unless it failed transiently
+func (b *circuitBreaker) record(err error) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.lock.Lock()
+	var notify func()
+	if err == nil {
+		b.failures = 0
+		if b.state != circuitClosed {
+			b.probing = false
+			b.setState(circuitClosed)
+			notify = b.notify(false, "api server recovered")
+		}
+	} else {
+		b.failures++
+		b.lastErr = err.Error()
+		if b.state == circuitHalfOpen || (b.state == circuitClosed && b.failures >= b.threshold) {
+			wasClosed := b.state == circuitClosed
+			b.probing = false
+			b.openedAt = time.Now()
+			b.setState(circuitOpen)
+			if wasClosed {
+				metrics.ApiCircuitOpened.Inc()
+				notify = b.notify(true, fmt.Sprintf("%d consecutive failures, last error: %s", b.failures, b.lastErr))
+			}
+		}
+	}
+	b.lock.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+func (b *circuitBreaker) setState(state circuitState) {
+	if b.state != state {
+		util.ApiLogger.Info("api circuit breaker state changed", "from", b.state.String(), "to", state.String())
+	}
+	b.state = state
+	metrics.ApiCircuitState.Set(float64(state))
+}
+
+// notify returns the call of the listener, to be made without holding the lock
+func (b *circuitBreaker) notify(open bool, reason string) func() {
+	listener := b.listener
+	if listener == nil {
+		return nil
+	}
+	return func() { listener(open, reason) }
+}
+
+func (b *circuitBreaker) openError() error {
+	return &ApiError{
+		Type:    ErrorCircuitOpen,
+		Code:    -1,
+		Message: fmt.Sprintf("api server circuit breaker is open, last error: %s", b.lastErr),
+	}
+}
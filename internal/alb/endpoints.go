@@ -0,0 +1,113 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// apiEndpoint is one address of the api server
+type apiEndpoint struct {
+	addr    string
+	healthy bool
+	retryAt time.Time // an unhealthy endpoint is tried again after it
+}
+
+// apiEndpoints tracks the health of the api server addresses and decides the order to try them.
+// An endpoint becomes unhealthy on a connection error, and is preferred again after it responds.
+type apiEndpoints struct {
+	lock       sync.Mutex
+	endpoints  []*apiEndpoint
+	roundRobin bool
+	next       int
+	cooldown   time.Duration
+}
+
+func newApiEndpoints(addrs []string, roundRobin bool, cooldown time.Duration) *apiEndpoints {
+	e := &apiEndpoints{
+		endpoints:  make([]*apiEndpoint, 0, len(addrs)),
+		roundRobin: roundRobin,
+		cooldown:   cooldown,
+	}
+	for _, addr := range addrs {
+		e.endpoints = append(e.endpoints, &apiEndpoint{addr: addr, healthy: true})
+		metrics.ApiServerHealthy.WithLabelValues(addr).Set(1)
+	}
+	return e
+}
+
+// order returns the indexes of the endpoints to try for a request. Healthy endpoints and the ones
+// whose cooldown elapsed come first, in list order for failover, or starting from the next one for
+// round-robin; the others are tried at last.
+func (e *apiEndpoints) order() []int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	n := len(e.endpoints)
+	start := 0
+	if e.roundRobin {
+		start = e.next % n
+		e.next++
+	}
+
+	now := time.Now()
+	eligible := make([]int, 0, n)
+	others := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		idx := (start + i) % n
+		ep := e.endpoints[idx]
+		if ep.healthy || !now.Before(ep.retryAt) {
+			eligible = append(eligible, idx)
+		} else {
+			others = append(others, idx)
+		}
+	}
+	return append(eligible, others...)
+}
+
+func (e *apiEndpoints) addr(idx int) string {
+	return e.endpoints[idx].addr
+}
+
+// markSucceeded records the endpoint responded
+func (e *apiEndpoints) markSucceeded(idx int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	ep := e.endpoints[idx]
+	if !ep.healthy {
+		util.ApiLogger.Info("api server endpoint recovered", "addr", ep.addr)
+	}
+	ep.healthy = true
+	metrics.ApiServerHealthy.WithLabelValues(ep.addr).Set(1)
+}
+
+// markFailed records the endpoint failed to connect
+func (e *apiEndpoints) markFailed(idx int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	ep := e.endpoints[idx]
+	if ep.healthy {
+		util.ApiLogger.Info("api server endpoint unhealthy", "addr", ep.addr, "cooldown", e.cooldown.String())
+	}
+	ep.healthy = false
+	ep.retryAt = time.Now().Add(e.cooldown)
+	metrics.ApiServerHealthy.WithLabelValues(ep.addr).Set(0)
+}
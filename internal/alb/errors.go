@@ -0,0 +1,55 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"github.com/bfenetworks/service-controller/pkg/bfeapi"
+)
+
+// ErrorType classifies the errors of api calls, the same way as the public client does
+type ErrorType = bfeapi.ErrorType
+
+const (
+	ErrorNotFound        = bfeapi.ErrorNotFound        // the pool does not exist
+	ErrorProductNotFound = bfeapi.ErrorProductNotFound // the product does not exist
+	ErrorConflict        = bfeapi.ErrorConflict        // the pool already exists or is being changed
+	ErrorInvalid         = bfeapi.ErrorInvalid         // the request is rejected by validation
+	ErrorUnauthorized    = bfeapi.ErrorUnauthorized    // the token is invalid or not permitted
+	ErrorTransient       = bfeapi.ErrorTransient       // network error, timeout, throttling or server error, may succeed later
+	ErrorUnknown         = bfeapi.ErrorUnknown
+
+	ErrorCircuitOpen ErrorType = "CircuitOpen" // not sent as the api server is regarded as down
+)
+
+// ApiError is the error of an api call, Code is ErrNum of the result, or http status code if no
+// result; -1 if no response
+type ApiError = bfeapi.Error
+
+var (
+	// ErrorTypeOf returns the type of err, ErrorUnknown if it is not an ApiError
+	ErrorTypeOf = bfeapi.ErrorTypeOf
+
+	IsNotFound        = bfeapi.IsNotFound
+	IsProductNotFound = bfeapi.IsProductNotFound
+	IsConflict        = bfeapi.IsConflict
+	IsInvalid         = bfeapi.IsInvalid
+	IsUnauthorized    = bfeapi.IsUnauthorized
+	IsTransient       = bfeapi.IsTransient
+)
+
+// IsCircuitOpen checks whether err is caused by the open circuit breaker
+func IsCircuitOpen(err error) bool {
+	return ErrorTypeOf(err) == ErrorCircuitOpen
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/bfenetworks/service-controller/internal/metrics"
+)
+
+// apiLimiter bounds the rate and the concurrency of requests to the api server.
+// When either is exhausted, requests queue by product and are let through in turn
+// across products, so a product with many changes can not starve the others.
+type apiLimiter struct {
+	rate        *rate.Limiter // nil means no rate limit
+	maxInFlight int           // 0 means no limit
+
+	lock     sync.Mutex
+	inFlight int
+	queues   map[string][]*apiWaiter
+	order    []string // products with waiters, in the order to serve
+	timer    *time.Timer
+}
+
+// apiWaiter is a request waiting for its turn
+type apiWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+func newApiLimiter(qps float64, burst int, maxInFlight int) *apiLimiter {
+	l := &apiLimiter{
+		maxInFlight: maxInFlight,
+		queues:      make(map[string][]*apiWaiter),
+	}
+	if qps > 0 {
+		if burst < 1 {
+			burst = 1
+		}
+		l.rate = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+	return l
+}
+
+// acquire waits for the turn of a request of product, the returned func must be called when
+// the request finishes
+func (l *apiLimiter) acquire(ctx context.Context, product string) (func(), error) {
+	start := time.Now()
+	w := &apiWaiter{ready: make(chan struct{})}
+
+	l.lock.Lock()
+	if len(l.order) == 0 && l.admitLocked() {
+		l.inFlight++
+		metrics.ApiServerInFlight.Set(float64(l.inFlight))
+		l.lock.Unlock()
+		metrics.ApiServerWaitSeconds.WithLabelValues(product).Observe(0)
+		return l.release, nil
+	}
+
+	if len(l.queues[product]) == 0 {
+		l.order = append(l.order, product)
+	}
+	l.queues[product] = append(l.queues[product], w)
+	metrics.ApiServerQueued.Inc()
+	l.dispatchLocked()
+	l.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		metrics.ApiServerWaitSeconds.WithLabelValues(product).Observe(time.Since(start).Seconds())
+		return l.release, nil
+	case <-ctx.Done():
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if w.granted {
+		// granted while giving up, pass the turn on
+		l.inFlight--
+		metrics.ApiServerInFlight.Set(float64(l.inFlight))
+		l.dispatchLocked()
+	} else {
+		l.removeLocked(product, w)
+	}
+	return nil, fmt.Errorf("fail to wait for api rate limiter: %s", ctx.Err())
+}
+
+// release ends a request and lets the next one through
+func (l *apiLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inFlight--
+	metrics.ApiServerInFlight.Set(float64(l.inFlight))
+	l.dispatchLocked()
+}
+
+// admitLocked checks whether one more request can be sent now, and takes a token if so
+func (l *apiLimiter) admitLocked() bool {
+	if l.maxInFlight > 0 && l.inFlight >= l.maxInFlight {
+		return false
+	}
+	return l.rate == nil || l.rate.Allow()
+}
+
+// dispatchLocked lets the queued requests through, one product after another
+func (l *apiLimiter) dispatchLocked() {
+	for len(l.order) > 0 {
+		if l.maxInFlight > 0 && l.inFlight >= l.maxInFlight {
+			// dispatched again on release
+			return
+		}
+		if l.rate != nil && !l.rate.Allow() {
+			l.scheduleLocked()
+			return
+		}
+
+		product := l.order[0]
+		queue := l.queues[product]
+		w := queue[0]
+		l.order = l.order[1:]
+		if len(queue) > 1 {
+			l.queues[product] = queue[1:]
+			// back to the end of the line
+			l.order = append(l.order, product)
+		} else {
+			delete(l.queues, product)
+		}
+
+		w.granted = true
+		l.inFlight++
+		metrics.ApiServerInFlight.Set(float64(l.inFlight))
+		metrics.ApiServerQueued.Dec()
+		close(w.ready)
+	}
+}
+
+// scheduleLocked dispatches again when the next token is available
+func (l *apiLimiter) scheduleLocked() {
+	if l.timer != nil {
+		return
+	}
+
+	delay := time.Duration((1 - l.rate.Tokens()) / float64(l.rate.Limit()) * float64(time.Second))
+	if delay < time.Millisecond {
+		delay = time.Millisecond
+	}
+	l.timer = time.AfterFunc(delay, func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.timer = nil
+		l.dispatchLocked()
+	})
+}
+
+// removeLocked removes a waiter given up before its turn
+func (l *apiLimiter) removeLocked(product string, w *apiWaiter) {
+	queue := l.queues[product]
+	for i, cur := range queue {
+		if cur == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			metrics.ApiServerQueued.Dec()
+			break
+		}
+	}
+	if len(queue) > 0 {
+		l.queues[product] = queue
+		return
+	}
+
+	delete(l.queues, product)
+	for i, cur := range l.order {
+		if cur == product {
+			l.order = append(l.order[:i], l.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// productOfURI returns the product a request is for, empty for requests not of a product
+func productOfURI(uri string) string {
+	rest, ok := strings.CutPrefix(uri, productPath+"/")
+	if !ok {
+		return ""
+	}
+	product, _, _ := strings.Cut(rest, "/")
+	return product
+}
@@ -16,49 +16,100 @@ package openapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
-	"strings"
 	"time"
 
-	"github.com/bfenetworks/service-controller/internal/alb/apis"
-	"github.com/bfenetworks/service-controller/internal/alb/apis/product_pool"
-	"github.com/bfenetworks/service-controller/internal/option"
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	"github.com/bfenetworks/service-controller/internal/option/externalLB"
 	util "github.com/bfenetworks/service-controller/internal/util"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
 )
 
 const (
 	version = "/open-api/v1"
 
+	productPath     = version + "/products"
 	productPoolPath = version + "/products/%s/instance-pools"
 )
 
 type OpenApiClient struct {
-	remote string
-	token  string
-	client *http.Client
+	endpoints *apiEndpoints
+	token     *apiToken
+	client    *reloadingClient
+
+	// timeout of each kind of operation, by http method
+	timeouts map[string]time.Duration
+	retry    retryPolicy
+	breaker  *circuitBreaker
+	limiter  *apiLimiter
 }
 
-func NewOpenApiClient(addr, token string, timeout int) *OpenApiClient {
+func NewOpenApiClient(opts *externalLB.Options) (*OpenApiClient, error) {
+	client, err := newReloadingClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	token, err := newApiToken(opts.Token, opts.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	read := time.Duration(opts.GetReadTimeout()) * time.Millisecond
+	write := time.Duration(opts.GetWriteTimeout()) * time.Millisecond
 	return &OpenApiClient{
-		remote: addr,
+		endpoints: newApiEndpoints(opts.GetApiServerAddrs(), opts.BalanceMode == externalLB.BalanceModeRoundRobin,
+			time.Duration(opts.UnhealthyCooldown)*time.Millisecond),
 		token:  token,
-		client: &http.Client{
-			Timeout: time.Duration(timeout) * time.Millisecond,
+		client: client,
+		timeouts: map[string]time.Duration{
+			http.MethodGet:    read,
+			http.MethodPost:   write,
+			http.MethodPatch:  write,
+			http.MethodDelete: time.Duration(opts.GetDeleteTimeout()) * time.Millisecond,
+		},
+		retry: retryPolicy{
+			retries:   opts.Retries,
+			baseDelay: time.Duration(opts.RetryBaseDelay) * time.Millisecond,
+			maxDelay:  time.Duration(opts.RetryMaxDelay) * time.Millisecond,
 		},
+		breaker: newCircuitBreaker(opts.CircuitThreshold, time.Duration(opts.CircuitOpenDuration)*time.Millisecond),
+		limiter: newApiLimiter(opts.QPS, opts.Burst, opts.MaxInFlight),
+	}, nil
+}
+
+// timeoutOf returns the timeout of the operation of method
+func (c *OpenApiClient) timeoutOf(method string) time.Duration {
+	if timeout, ok := c.timeouts[method]; ok {
+		return timeout
 	}
+	return c.timeouts[http.MethodGet]
 }
 
-func (c *OpenApiClient) CreateProductPool(product string, req *product_pool.UpsertParam) (*product_pool.OneRsp, int, error) {
+func (c *OpenApiClient) CreateProductPool(ctx context.Context, product string, req *product_pool.UpsertParam) (*product_pool.OneRsp, int, error) {
 	uri := c.genURI(productPoolPath, product, "")
-	result, err := c.doReq(uri, http.MethodPost, req)
+	// the pool may be created by a failed attempt, so retry only if it does not exist
+	result, err := c.doReqWithRetry(ctx, uri, http.MethodPost, req, func(ctx context.Context) (*bfeapi.Result, bool, error) {
+		result, err := c.doReq(ctx, c.genURI(productPoolPath, product, *req.Name), http.MethodGet, nil)
+		if err == nil && result.ErrNum != http.StatusOK {
+			err = result.Err()
+		}
+		if IsNotFound(err) {
+			return nil, false, nil
+		}
+		return result, err == nil, err
+	})
 	if err != nil {
 		return nil, -1, err
 	}
 	if result.ErrNum != http.StatusOK {
-		return nil, result.ErrNum, fmt.Errorf("code:%d, %s", result.ErrNum, result.RetMsg)
+		return nil, result.ErrNum, result.Err()
 	}
 
 	rsp := &product_pool.OneRsp{}
@@ -69,14 +120,14 @@ func (c *OpenApiClient) CreateProductPool(product string, req *product_pool.Upse
 	return rsp, result.ErrNum, nil
 }
 
-func (c *OpenApiClient) ListProductPool(product string) (*[]string, int, error) {
+func (c *OpenApiClient) ListProductPool(ctx context.Context, product string) (*[]string, int, error) {
 	uri := c.genURI(productPoolPath, product, "")
-	result, err := c.doReq(uri, http.MethodGet, nil)
+	result, err := c.doReq(ctx, uri, http.MethodGet, nil)
 	if err != nil {
 		return nil, -1, err
 	}
 	if result.ErrNum != http.StatusOK {
-		return nil, result.ErrNum, fmt.Errorf("code:%d, error:%s", result.ErrNum, result.RetMsg)
+		return nil, result.ErrNum, result.Err()
 	}
 
 	rsp := &[]string{}
@@ -91,14 +142,14 @@ func (c *OpenApiClient) ListProductPool(product string) (*[]string, int, error)
 	return rsp, result.ErrNum, nil
 }
 
-func (c *OpenApiClient) GetProductPool(product string, name string) (*product_pool.OneRsp, int, error) {
+func (c *OpenApiClient) GetProductPool(ctx context.Context, product string, name string) (*product_pool.OneRsp, int, error) {
 	uri := c.genURI(productPoolPath, product, name)
-	result, err := c.doReq(uri, http.MethodGet, nil)
+	result, err := c.doReq(ctx, uri, http.MethodGet, nil)
 	if err != nil {
 		return nil, -1, err
 	}
 	if result.ErrNum != http.StatusOK {
-		return nil, result.ErrNum, fmt.Errorf("code:%d, error:%s", result.ErrNum, result.RetMsg)
+		return nil, result.ErrNum, result.Err()
 	}
 
 	rsp := &product_pool.OneRsp{}
@@ -109,14 +160,16 @@ func (c *OpenApiClient) GetProductPool(product string, name string) (*product_po
 	return rsp, result.ErrNum, nil
 }
 
-func (c *OpenApiClient) UpdateProductPool(product string, req *product_pool.UpsertParam) (*product_pool.OneRsp, int, error) {
+// UpdateProductPool replaces the instances of the pool with req.Instances, the full list, so it is
+// safe to send again after it may have been applied
+func (c *OpenApiClient) UpdateProductPool(ctx context.Context, product string, req *product_pool.UpsertParam) (*product_pool.OneRsp, int, error) {
 	uri := c.genURI(productPoolPath, product, *req.Name)
-	result, err := c.doReq(uri, http.MethodPatch, req)
+	result, err := c.doReq(ctx, uri, http.MethodPatch, req)
 	if err != nil {
 		return nil, -1, err
 	}
 	if result.ErrNum != http.StatusOK {
-		return nil, result.ErrNum, fmt.Errorf("code:%d, error:%s", result.ErrNum, result.RetMsg)
+		return nil, result.ErrNum, result.Err()
 	}
 
 	rsp := &product_pool.OneRsp{}
@@ -127,26 +180,31 @@ func (c *OpenApiClient) UpdateProductPool(product string, req *product_pool.Upse
 	return rsp, result.ErrNum, nil
 }
 
-func (c *OpenApiClient) DeleteProductPool(product string, name string) error {
+func (c *OpenApiClient) DeleteProductPool(ctx context.Context, product string, name string) error {
 	uri := c.genURI(productPoolPath, product, name)
-	result, err := c.doReq(uri, http.MethodDelete, nil)
-
-	if err != nil {
-		if result.ErrNum == http.StatusUnprocessableEntity && strings.Contains(err.Error(), "Product Not Exist") {
-			return nil
-		}
-		return err
+	result, err := c.doReq(ctx, uri, http.MethodDelete, nil)
+	if err == nil && result.ErrNum != http.StatusOK {
+		err = result.Err()
 	}
 
-	//err == nil
-	if result.ErrNum == http.StatusUnprocessableEntity && strings.Contains(result.RetMsg, "Product Not Exist") {
+	if IsNotFound(err) || IsProductNotFound(err) {
+		// already gone
 		return nil
 	}
+	return err
+}
 
-	if result.ErrNum != http.StatusOK && result.ErrNum != http.StatusNotFound {
-		return fmt.Errorf("code:%d, error:%s", result.ErrNum, result.RetMsg)
+// Ping checks the api server is reachable and the token is accepted
+func (c *OpenApiClient) Ping(ctx context.Context) error {
+	result, err := c.doReq(ctx, productPath, http.MethodGet, nil)
+	if err != nil {
+		return err
 	}
-	return err
+	if result.ErrNum != http.StatusOK {
+		return result.Err()
+	}
+
+	return nil
 }
 
 func (c *OpenApiClient) genURI(path, product, name string) string {
@@ -157,58 +215,104 @@ func (c *OpenApiClient) genURI(path, product, name string) string {
 	return uri
 }
 
-func (c *OpenApiClient) doReq(uri, method string, obj interface{}) (*apis.Result, error) {
-	var apiAddr string
-	var useIdx int
+// doReq sends the request, and retries it on transient failures if it is idempotent
+func (c *OpenApiClient) doReq(ctx context.Context, uri, method string, obj interface{}) (*bfeapi.Result, error) {
+	return c.doReqWithRetry(ctx, uri, method, obj, nil)
+}
+
+// doReqOnce sends the request to the api server endpoints in turn, until one of them responds.
+// A POST goes to the next endpoint only if it failed to connect, so it is never sent twice.
+// It also returns the delay asked by Retry-After of the response.
+func (c *OpenApiClient) doReqOnce(ctx context.Context, uri, method string, obj interface{}) (*bfeapi.Result, time.Duration, error) {
 	var err error
-	var result *apis.Result
+	var result *bfeapi.Result
+	var retryAfter time.Duration
+
+	order := c.endpoints.order()
+	for i, useIdx := range order {
+		apiAddr := c.endpoints.addr(useIdx)
+
+		// cancelled on shutdown or loss of leadership through ctx
+		reqCtx, cancel := context.WithTimeout(ctx, c.timeoutOf(method))
+		isHttpDoFailed := false
+		srv_url := apiAddr + uri
+		result, retryAfter, isHttpDoFailed, err = c.doReqImpl(reqCtx, srv_url, method, obj)
+		cancel()
+
+		util.ApiLogger.Info("doReq", "url", srv_url, "method", method, "iserr", err != nil, "useIdx", useIdx, "isHttpDoFailed", isHttpDoFailed)
+
+		if !isHttpDoFailed {
+			c.endpoints.markSucceeded(useIdx)
+			metrics.ApiServerRequests.WithLabelValues(apiAddr, requestResult(err)).Inc()
+			return result, retryAfter, err
+		}
+		c.endpoints.markFailed(useIdx)
+		metrics.ApiServerRequests.WithLabelValues(apiAddr, "connection_error").Inc()
 
-	apiAddr = option.Opts.ExternalLB.ApiServerAddr
+		if ctx.Err() != nil {
+			// no time left for other endpoints
+			break
+		}
+		if !isIdempotent(method) && !isDialError(err) {
+			// the request may have been sent, left to doReqWithRetry and its check
+			break
+		}
+		if i < len(order)-1 {
+			metrics.ApiServerFailovers.WithLabelValues(apiAddr).Inc()
+		}
+	}
 
-	isHttpDoFailed := false
-	srv_url := apiAddr + uri
-	result, isHttpDoFailed, err = c.doReqImpl(srv_url, method, obj)
+	return result, 0, err
+}
 
-	util.ApiLogger.Info("doReq", "url", srv_url, "method", method, "iserr", err != nil, "useIdx", useIdx, "isHttpDoFailed", isHttpDoFailed)
+// isIdempotent checks whether a request of method can be sent again without side effects.
+// A PATCH is, as it always carries the full instance list of the pool, see UpdateProductPool.
+func isIdempotent(method string) bool {
+	return method != http.MethodPost
+}
 
-	return result, err
+// isDialError checks whether a request failed before being sent, i.e. on connecting to the server
+// or the proxy
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial" || opErr.Op == "proxyconnect"
+	}
+	return false
 }
 
-func (c *OpenApiClient) doReqImpl(url, method string, obj interface{}) (*apis.Result, bool, error) {
+// requestResult returns the result label of a request which got a response
+func requestResult(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func (c *OpenApiClient) doReqImpl(ctx context.Context, url, method string, obj interface{}) (*bfeapi.Result, time.Duration, bool, error) {
 	var body io.Reader
 	if obj != nil {
 		jsonStr, err := json.Marshal(obj)
 		if err != nil {
-			return nil, false, err
+			return nil, 0, false, err
 		}
 		body = bytes.NewBuffer(jsonStr)
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return nil, false, err
+		return nil, 0, false, err
 	}
-	req.Header.Add("Authorization", c.token)
+	req.Header.Add("Authorization", c.token.get())
 	if obj != nil {
 		req.Header.Add("Content-Type", "application/json")
 	}
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, true, err
-	}
-
-	result := &apis.Result{}
-
-	resbody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return result, false, fmt.Errorf("fail to read response body. error:%s", err.Error())
-	}
-
-	resbodyString := string(resbody)
-	err = json.Unmarshal(resbody, result)
+	resp, err := c.client.get().Do(req)
 	if err != nil {
-		return result, false, fmt.Errorf("fail to unmarshal respone result error: %s, resbody:%s", err.Error(), resbodyString)
+		return nil, 0, true, bfeapi.NewTransportError(err)
 	}
 
-	return result, false, nil
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	result, err := bfeapi.ReadResult(resp)
+	return result, retryAfter, false, err
 }
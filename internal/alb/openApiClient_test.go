@@ -0,0 +1,205 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bfenetworks/service-controller/internal/option/externalLB"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
+)
+
+// newTestClient returns a client of a fake api server with handler, and the number of connections accepted
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*OpenApiClient, *int32) {
+	conns := new(int32)
+	s := httptest.NewUnstartedServer(handler)
+	s.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(conns, 1)
+		}
+	}
+	s.Start()
+	t.Cleanup(s.Close)
+
+	opts := externalLB.NewOptions()
+	opts.ApiServerAddr = s.URL
+	opts.Token = "Token test"
+	opts.Retries = 0
+	opts.CircuitThreshold = 0
+	c, err := NewOpenApiClient(opts)
+	if err != nil {
+		t.Fatalf("NewOpenApiClient: %s", err)
+	}
+	return c, conns
+}
+
+func TestResponseBodyClosed(t *testing.T) {
+	c, conns := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/bad") {
+			w.WriteHeader(http.StatusBadGateway)
+			io.WriteString(w, "<html>"+strings.Repeat("x", 4096)+"</html>")
+			return
+		}
+		io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"demo.web"}}`)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if _, _, err := c.GetProductPool(ctx, "demo", "demo.web"); err != nil {
+			t.Fatalf("GetProductPool: %s", err)
+		}
+		if _, _, err := c.GetProductPool(ctx, "demo", "bad"); err == nil {
+			t.Fatalf("GetProductPool of error page should fail")
+		}
+	}
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Errorf("%d connections for sequential requests, want 1", n)
+	}
+}
+
+func TestStatusOnlyError(t *testing.T) {
+	var status int32
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		io.WriteString(w, "<html><title>proxy error</title>"+strings.Repeat("x", 1024)+"</html>")
+	})
+
+	ctx := context.Background()
+	cases := []struct {
+		status int
+		want   ErrorType
+	}{
+		{http.StatusNotFound, ErrorUnknown},
+		{http.StatusConflict, ErrorUnknown},
+		{http.StatusUnprocessableEntity, ErrorUnknown},
+		{http.StatusTooManyRequests, ErrorTransient},
+		{http.StatusBadGateway, ErrorTransient},
+	}
+	for _, tc := range cases {
+		atomic.StoreInt32(&status, int32(tc.status))
+		_, _, err := c.GetProductPool(ctx, "demo", "demo.web")
+		if err == nil || ErrorTypeOf(err) != tc.want {
+			t.Errorf("html page of status %d: got %v, want type %s", tc.status, err, tc.want)
+			continue
+		}
+		msg := err.Error()
+		if !strings.Contains(msg, http.StatusText(tc.status)) || !strings.Contains(msg, "<title>proxy error</title>") {
+			t.Errorf("error should carry the status and the head of the body, got %s", msg)
+		}
+	}
+
+	// a pool is not regarded as gone by the error page of a proxy
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	if err := c.DeleteProductPool(ctx, "demo", "demo.web"); err == nil {
+		t.Errorf("DeleteProductPool should fail on html 404")
+	}
+}
+
+func TestResultErrorPassedThrough(t *testing.T) {
+	var reply atomic.Value
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rsp := reply.Load().([2]string)
+		w.WriteHeader(map[string]int{"404": http.StatusNotFound, "422": http.StatusUnprocessableEntity}[rsp[0]])
+		io.WriteString(w, rsp[1])
+	})
+
+	ctx := context.Background()
+	reply.Store([2]string{"404", `{"ErrNum":404,"ErrMsg":"Pool Not Exist"}`})
+	result, err := c.doReq(ctx, c.genURI(productPoolPath, "demo", "demo.web"), http.MethodGet, nil)
+	if err != nil || result == nil || result.ErrNum != http.StatusNotFound || result.ErrMsg != "Pool Not Exist" {
+		t.Fatalf("error result should be passed through, got %v, %v", result, err)
+	}
+	if _, _, err := c.GetProductPool(ctx, "demo", "demo.web"); !IsNotFound(err) {
+		t.Errorf("GetProductPool: got %v, want NotFound", err)
+	}
+	if err := c.DeleteProductPool(ctx, "demo", "demo.web"); err != nil {
+		t.Errorf("DeleteProductPool of nonexistent pool: %s", err)
+	}
+
+	reply.Store([2]string{"422", `{"ErrNum":422,"ErrMsg":"Product Not Exist"}`})
+	if _, _, err := c.GetProductPool(ctx, "demo", "demo.web"); !IsProductNotFound(err) {
+		t.Errorf("GetProductPool: got %v, want ProductNotFound", err)
+	}
+}
+
+// dropAfterRead returns a handler reading the request and closing the connection without a response,
+// i.e. the request may have been applied, for the first drops requests; later ones succeed
+func dropAfterRead(t *testing.T, drops int32, patches *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK"}`)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		if atomic.AddInt32(patches, 1) > drops {
+			io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"demo.web"}}`)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %s", err)
+			return
+		}
+		conn.Close()
+	}
+}
+
+func TestPatchResentAfterWritten(t *testing.T) {
+	pool := "demo.web"
+	param := &product_pool.UpsertParam{Name: &pool}
+
+	// retried on the same address
+	patches := new(int32)
+	c, _ := newTestClient(t, dropAfterRead(t, 1, patches))
+	c.retry = retryPolicy{retries: 1, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+	if _, _, err := c.UpdateProductPool(context.Background(), "demo", param); err != nil {
+		t.Errorf("PATCH failed after written should be retried: %s", err)
+	}
+	if n := atomic.LoadInt32(patches); n != 2 {
+		t.Errorf("%d PATCH sent, want 2", n)
+	}
+
+	// failed over to the next address without retry
+	dropped, served := new(int32), new(int32)
+	s1 := httptest.NewServer(dropAfterRead(t, 1<<10, dropped))
+	t.Cleanup(s1.Close)
+	s2 := httptest.NewServer(dropAfterRead(t, 0, served))
+	t.Cleanup(s2.Close)
+	opts := externalLB.NewOptions()
+	opts.ApiServerAddr = s1.URL + "," + s2.URL
+	opts.Token = "Token test"
+	opts.Retries = 0
+	opts.CircuitThreshold = 0
+	c, err := NewOpenApiClient(opts)
+	if err != nil {
+		t.Fatalf("NewOpenApiClient: %s", err)
+	}
+	if _, _, err := c.UpdateProductPool(context.Background(), "demo", param); err != nil {
+		t.Errorf("PATCH failed after written should fail over: %s", err)
+	}
+	if atomic.LoadInt32(dropped) != 1 || atomic.LoadInt32(served) != 1 {
+		t.Errorf("PATCH sent %d times to the dropping address and %d times to the next, want 1 and 1",
+			atomic.LoadInt32(dropped), atomic.LoadInt32(served))
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
+)
+
+// tags of instances recording the owner of a pool.
+// BFE pools have no metadata of their own, so the owner is written on every instance.
+const (
+	TagManagedBy = "managed-by"
+	TagCluster   = "cluster"
+	TagService   = "service" // namespace/name
+	TagUID       = "uid"
+
+	// written instead of the tags above on the instances of a released pool, which can be adopted again
+	TagReleasedBy = "released-by"
+)
+
+// PoolOwner is the service a pool is published for
+type PoolOwner struct {
+	ControllerID string
+	Cluster      string
+	Namespace    string
+	Name         string
+	UID          string
+}
+
+// Ownership decides which pools may be modified or deleted
+type Ownership struct {
+	Owner  PoolOwner
+	Adopt  bool // modify or delete pools not owned by Owner
+	Shared bool // the pool is shared with the same service in other clusters
+
+	// names of the pools recorded as written for Owner. Pools without ownership tags, i.e. empty ones
+	// or the ones written by earlier versions, are owned only if recorded.
+	Recorded map[string]bool
+}
+
+// NotOwnedError is returned when a pool to modify is owned by others
+type NotOwnedError struct {
+	Pool  string
+	Owner string
+
+	// the owner is the service of the same name in another cluster, which can not be told apart
+	// by the empty cluster name but only by uid
+	Collision bool
+}
+
+func (e *NotOwnedError) Error() string {
+	if e.Collision {
+		return fmt.Sprintf("pool %s collides with %s, the cluster name is empty, set distinct -k8s-cluster-name", e.Pool, e.Owner)
+	}
+	return fmt.Sprintf("pool %s is not owned by this service but %s, enable adoption to take it over", e.Pool, e.Owner)
+}
+
+// Service returns the value of TagService
+func (o PoolOwner) Service() string {
+	return o.Namespace + "/" + o.Name
+}
+
+// Tags returns the tags written on the instances of the pools of o
+func (o PoolOwner) Tags() map[string]string {
+	tags := map[string]string{
+		TagManagedBy: o.ControllerID,
+		TagCluster:   o.Cluster,
+		TagService:   o.Service(),
+	}
+	if o.UID != "" {
+		tags[TagUID] = o.UID
+	}
+	return tags
+}
+
+// owns checks whether the instances of a pool are written by o. The uid is not compared if the
+// cluster name is given, so a service recreated with the same name keeps its pools.
+func (o PoolOwner) owns(tags map[string]string) bool {
+	return o.sameName(tags) && !o.collides(tags)
+}
+
+// sameName checks whether the instance is written for the service of the same name and cluster
+func (o PoolOwner) sameName(tags map[string]string) bool {
+	return tags[TagManagedBy] == o.ControllerID &&
+		tags[TagCluster] == o.Cluster &&
+		tags[TagService] == o.Service()
+}
+
+// collides checks whether the instance is written for the service of the same name but another uid.
+// Without cluster name, it is the only way to tell the same service of other clusters apart.
+func (o PoolOwner) collides(tags map[string]string) bool {
+	return o.Cluster == "" && o.UID != "" && tags[TagUID] != "" && tags[TagUID] != o.UID
+}
+
+// isPeer checks whether the instance is written for the same service in another cluster
+func (o PoolOwner) isPeer(tags map[string]string) bool {
+	return tags[TagManagedBy] != "" && tags[TagService] == o.Service() && !o.owns(tags)
+}
+
+// splitInstances splits the instances of a shared pool into the ones to be replaced by o,
+// and the ones of the same service in other clusters
+func splitInstances(instances []*product_pool.Instance, o PoolOwner) ([]*product_pool.Instance, []*product_pool.Instance) {
+	own := make([]*product_pool.Instance, 0, len(instances))
+	peers := make([]*product_pool.Instance, 0, len(instances))
+	for _, ins := range instances {
+		if o.isPeer(ins.Tags) {
+			peers = append(peers, ins)
+		} else {
+			own = append(own, ins)
+		}
+	}
+	return own, peers
+}
+
+// isLegacyTags checks whether tags are the placeholder written before ownership tags were introduced
+func isLegacyTags(tags map[string]string) bool {
+	return len(tags) == 1 && tags["key"] == "value"
+}
+
+// releasedTags returns the tags of the instances of a pool released by the controller
+func releasedTags(controllerID string) map[string]string {
+	return map[string]string{TagReleasedBy: controllerID}
+}
+
+// checkOwnership returns NotOwnedError if any instance of the pool is not written by the owner.
+// An empty pool, and instances written by earlier versions, are accepted only if the pool is recorded
+// for the owner. With adoptUnmanaged, instances not written by any controller, including the released
+// ones, are accepted too.
+func checkOwnership(pool string, instances []*product_pool.Instance, ownership Ownership, adoptUnmanaged bool) error {
+	if ownership.Adopt {
+		return nil
+	}
+
+	recorded := ownership.Recorded[pool]
+	if len(instances) == 0 && !recorded && !adoptUnmanaged {
+		return &NotOwnedError{Pool: pool, Owner: "unknown, the pool is empty and not recorded by this service"}
+	}
+
+	for _, ins := range instances {
+		if ownership.Owner.owns(ins.Tags) {
+			continue
+		}
+		if isLegacyTags(ins.Tags) {
+			// written by versions without ownership tags, managed by the service recording the pool
+			if recorded {
+				continue
+			}
+			return &NotOwnedError{Pool: pool, Owner: "the service recording it, written by an earlier version"}
+		}
+		if adoptUnmanaged && ins.Tags[TagManagedBy] == "" {
+			continue
+		}
+		if ownership.Shared && ownership.Owner.isPeer(ins.Tags) {
+			continue
+		}
+		return &NotOwnedError{
+			Pool:      pool,
+			Owner:     describeOwner(ins.Tags),
+			Collision: ownership.Owner.sameName(ins.Tags),
+		}
+	}
+	return nil
+}
+
+func describeOwner(tags map[string]string) string {
+	if tags[TagManagedBy] == "" && tags[TagReleasedBy] != "" {
+		return "none, released by " + tags[TagReleasedBy]
+	}
+	if tags[TagManagedBy] == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("service %s(uid %s) of cluster %q managed by %s", tags[TagService], tags[TagUID],
+		tags[TagCluster], tags[TagManagedBy])
+}
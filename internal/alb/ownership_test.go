@@ -0,0 +1,136 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bfenetworks/service-controller/internal/option/externalLB"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
+)
+
+var testOwner = PoolOwner{ControllerID: "bfe-service-controller", Cluster: "gz", Namespace: "default", Name: "web", UID: "uid-web"}
+
+func instanceWithTags(ip string, tags map[string]string) *product_pool.Instance {
+	return &product_pool.Instance{Hostname: ip, IP: ip, Weight: 1, Ports: map[string]int{"Default": 80}, Tags: tags}
+}
+
+func TestCheckOwnership(t *testing.T) {
+	other := testOwner
+	other.Name = "api"
+	legacy := map[string]string{"key": "value"}
+	released := releasedTags(testOwner.ControllerID)
+	unmanaged := map[string]string{"team": "web"}
+
+	cases := []struct {
+		name     string
+		tags     map[string]string
+		recorded bool
+		adopt    bool
+		owned    bool
+	}{
+		{name: "owned", tags: testOwner.Tags(), owned: true},
+		{name: "other service", tags: other.Tags(), adopt: true},
+		{name: "legacy recorded", tags: legacy, recorded: true, owned: true},
+		{name: "legacy not recorded", tags: legacy},
+		{name: "legacy adopted by another service", tags: legacy, adopt: true},
+		{name: "unmanaged", tags: unmanaged},
+		{name: "unmanaged adopted", tags: unmanaged, adopt: true, owned: true},
+		{name: "released", tags: released},
+		{name: "released adopted", tags: released, adopt: true, owned: true},
+	}
+	for _, tc := range cases {
+		ownership := Ownership{Owner: testOwner, Recorded: map[string]bool{"demo.web": tc.recorded}}
+		instances := []*product_pool.Instance{instanceWithTags("10.0.0.1", tc.tags)}
+		err := checkOwnership("demo.web", instances, ownership, tc.adopt)
+		if owned := err == nil; owned != tc.owned {
+			t.Errorf("%s: owned %v, want %v, err %v", tc.name, owned, tc.owned, err)
+		}
+	}
+
+	// an empty pool carries no owner
+	ownership := Ownership{Owner: testOwner}
+	if err := checkOwnership("demo.web", nil, ownership, false); err == nil {
+		t.Errorf("empty pool not recorded should not be owned")
+	}
+	ownership.Recorded = map[string]bool{"demo.web": true}
+	if err := checkOwnership("demo.web", nil, ownership, false); err != nil {
+		t.Errorf("empty pool recorded should be owned, got %s", err)
+	}
+}
+
+func TestReleaseProductPool(t *testing.T) {
+	pool := &product_pool.OneRsp{
+		Name: "demo.web",
+		Instances: []*product_pool.Instance{
+			instanceWithTags("10.0.0.1", testOwner.Tags()),
+			instanceWithTags("10.0.0.2", map[string]string{"key": "value"}),
+		},
+	}
+
+	var lock sync.Mutex
+	var updated *product_pool.UpsertParam
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.Method == http.MethodPatch {
+			updated = &product_pool.UpsertParam{}
+			json.NewDecoder(r.Body).Decode(updated)
+			io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK","Data":{}}`)
+			return
+		}
+		data, _ := json.Marshal(pool)
+		io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK","Data":`+string(data)+`}`)
+	}))
+	defer s.Close()
+
+	opts := externalLB.NewOptions()
+	opts.ApiServerAddr = s.URL
+	opts.Token = "Token test"
+	p, err := NewAlbProvider(opts)
+	if err != nil {
+		t.Fatalf("NewAlbProvider: %s", err)
+	}
+
+	ownership := Ownership{Owner: testOwner, Recorded: map[string]bool{"demo.web": true}}
+	list := ProductPoolnameList{{Product: "demo", Poolname: "demo.web"}}
+	relnames, err := p.ReleaseProductPoolByList(context.Background(), list, ownership)
+	if err != nil || len(relnames) != 1 {
+		t.Fatalf("ReleaseProductPoolByList: %v, %v", relnames, err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if updated == nil || len(updated.Instances) != 2 {
+		t.Fatalf("instances of released pool should be kept, got %v", updated)
+	}
+	other := testOwner
+	other.Name = "api"
+	for _, ins := range updated.Instances {
+		if ins.Tags[TagManagedBy] != "" || ins.Tags[TagReleasedBy] != testOwner.ControllerID {
+			t.Errorf("ownership tags of %s should be replaced, got %v", ins.IP, ins.Tags)
+		}
+		// adoptable by another service
+		if err := checkOwnership("demo.web", []*product_pool.Instance{ins}, Ownership{Owner: other}, true); err != nil {
+			t.Errorf("released pool should be adoptable, got %s", err)
+		}
+	}
+}
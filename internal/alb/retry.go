@@ -0,0 +1,149 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	util "github.com/bfenetworks/service-controller/internal/util"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi"
+)
+
+// retryPolicy bounds the retries of a request failed transiently
+type retryPolicy struct {
+	retries   int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// retryCheck is called before retrying a request which is not idempotent. It returns the result to
+// use instead and true if the retry is not needed, e.g. the failed attempt took effect.
+type retryCheck func(ctx context.Context) (*bfeapi.Result, bool, error)
+
+// doReqWithRetry sends the request, and retries it with exponential backoff and jitter on transient
+// failures. A POST, the only request not idempotent, is retried only if check is given. Every attempt waits for its turn in the limiter.
+func (c *OpenApiClient) doReqWithRetry(ctx context.Context, uri, method string, obj interface{},
+	check retryCheck) (*bfeapi.Result, error) {
+	product := productOfURI(uri)
+	for attempt := 0; ; attempt++ {
+		release, err := c.limiter.acquire(ctx, product)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.breaker.allow(); err != nil {
+			release()
+			return nil, err
+		}
+		result, retryAfter, err := c.doReqOnce(ctx, uri, method, obj)
+		release()
+		if IsUnauthorized(requestError(result, err)) {
+			// the token may have been rotated, let the watchers reload it
+			c.token.reject()
+		}
+		if isRetryable(result, err) {
+			c.breaker.record(requestError(result, err))
+		} else {
+			c.breaker.record(nil)
+		}
+
+		if attempt >= c.retry.retries || !isRetryable(result, err) {
+			return result, err
+		}
+		if method == http.MethodPost && check == nil {
+			return result, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		if retryAfter > c.retry.maxDelay {
+			// the server asks for a longer break, leave it to the next reconcile
+			return result, err
+		}
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		util.ApiLogger.Info("retry request", "uri", uri, "method", method, "attempt", attempt+1, "delay", delay.String())
+		metrics.ApiServerRetries.WithLabelValues(method).Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+
+		if check != nil {
+			checked, done, cerr := check(ctx)
+			if cerr != nil {
+				// can not tell whether retrying is safe
+				util.ApiLogger.Info("check before retry failed", "uri", uri, "method", method, "err", cerr.Error())
+				return result, err
+			}
+			if done {
+				return checked, nil
+			}
+		}
+	}
+}
+
+// requestError returns the error of a request, including the one in its result
+func requestError(result *bfeapi.Result, err error) error {
+	if err == nil && result != nil && result.ErrNum != http.StatusOK {
+		return result.Err()
+	}
+	return err
+}
+
+// backoff returns the delay before the retry after attempt, with jitter in [d/2, d)
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.baseDelay << uint(attempt)
+	if d > p.maxDelay || d <= 0 {
+		d = p.maxDelay
+	}
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
+// isRetryable checks whether a request failed transiently
+func isRetryable(result *bfeapi.Result, err error) bool {
+	if err != nil {
+		return IsTransient(err)
+	}
+	return result != nil && result.ErrNum != http.StatusOK &&
+		bfeapi.ClassifyResult(result.ErrNum, result.ErrMsg) == ErrorTransient
+}
+
+// parseRetryAfter parses the Retry-After header in seconds or http date, 0 if absent or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bfenetworks/service-controller/internal/option/externalLB"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+const (
+	// tlsCheckInterval is the min interval of checking whether the certificate files changed
+	tlsCheckInterval = 10 * time.Second
+
+	// connections to the api server
+	dialTimeout             = 5 * time.Second
+	keepAlive               = 30 * time.Second
+	idleConnTimeout         = 90 * time.Second
+	tlsHandshakeTimeout     = 5 * time.Second
+	defaultIdleConnsPerHost = 16
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// reloadingClient provides the http client to the api server, rebuilt when the
+// CA bundle or client certificate files change
+type reloadingClient struct {
+	opts *externalLB.Options
+
+	lock      sync.Mutex
+	client    *http.Client
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+func newReloadingClient(opts *externalLB.Options) (*reloadingClient, error) {
+	c := &reloadingClient{opts: opts}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// get returns the current http client, reloading the certificate files if they changed
+func (c *reloadingClient) get() *http.Client {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.modTimes) > 0 && time.Since(c.checkedAt) >= tlsCheckInterval {
+		c.checkedAt = time.Now()
+		if c.changed() {
+			if err := c.reload(); err != nil {
+				// keep the current client, the files may be partially written
+				util.ApiLogger.Error(err, "fail to reload tls files, keep the current ones")
+			} else {
+				util.ApiLogger.Info("tls files reloaded")
+			}
+		}
+	}
+	return c.client
+}
+
+// changed checks whether any certificate file was modified since loaded
+func (c *reloadingClient) changed() bool {
+	for file, modTime := range c.modTimes {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload builds a new http client from the certificate files
+func (c *reloadingClient) reload() error {
+	files := []string{c.opts.TLSCAFile, c.opts.TLSCertFile, c.opts.TLSKeyFile}
+	modTimes := make(map[string]time.Time)
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("fail to stat tls file %s: %s", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	config, err := buildTLSConfig(c.opts)
+	if err != nil {
+		return err
+	}
+
+	transport, err := newTransport(c.opts)
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = config
+
+	old := c.client
+	// the deadline of every request is set by its context, see doReqOnce
+	c.client = &http.Client{Transport: transport}
+	c.modTimes = modTimes
+	c.checkedAt = time.Now()
+	if old != nil {
+		old.CloseIdleConnections()
+	}
+	return nil
+}
+
+// newTransport returns the transport to the api server, keeping enough idle connections
+// for the concurrent requests allowed by the limiter
+func newTransport(opts *externalLB.Options) (*http.Transport, error) {
+	idle := opts.MaxInFlight
+	if idle <= 0 {
+		idle = defaultIdleConnsPerHost
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          idle * len(opts.GetApiServerAddrs()),
+		MaxIdleConnsPerHost:   idle,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	switch opts.Proxy {
+	case "":
+		// HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	case externalLB.ProxyNone:
+		transport.Proxy = nil
+	default:
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %s: %s", opts.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
+
+// buildTLSConfig returns the tls config of connections to the api server
+func buildTLSConfig(opts *externalLB.Options) (*tls.Config, error) {
+	version, ok := tlsVersions[opts.TLSMinVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown tls version %q", opts.TLSMinVersion)
+	}
+	config := &tls.Config{
+		MinVersion: version,
+		ServerName: opts.TLSServerName,
+	}
+
+	if opts.TLSCAFile != "" {
+		pem, err := os.ReadFile(opts.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read tls ca file %s: %s", opts.TLSCAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in tls ca file %s", opts.TLSCAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to load tls client certificate %s: %s", opts.TLSCertFile, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
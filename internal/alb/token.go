@@ -0,0 +1,102 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiToken is the token of the api server. It is read from file if given, otherwise set by the
+// command line or the watcher of the token secret. The file is checked by a watcher off the request
+// path, see AlbProvider.ReloadTokenFile, and the watchers are woken up when the token is rejected.
+type apiToken struct {
+	lock    sync.RWMutex
+	value   string
+	file    string
+	modTime time.Time
+
+	// notified when the api server rejects the token, buffered so requests never block on it
+	rejected chan struct{}
+}
+
+func newApiToken(value, file string) (*apiToken, error) {
+	t := &apiToken{value: value, file: file, rejected: make(chan struct{}, 1)}
+	if file != "" {
+		if _, err := t.reloadFile(true); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// get returns the current token
+func (t *apiToken) get() string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.value
+}
+
+// set replaces the token
+func (t *apiToken) set(value string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.value = value
+}
+
+// reject notifies the watchers that the token is rejected, and may be out of date
+func (t *apiToken) reject() {
+	select {
+	case t.rejected <- struct{}{}:
+	default:
+		// a notification is pending already
+	}
+}
+
+// reloadFile reads the token from file if it changed since the last read, or if force.
+// It returns whether the token is reloaded.
+func (t *apiToken) reloadFile(force bool) (bool, error) {
+	info, err := os.Stat(t.file)
+	if err != nil {
+		return false, fmt.Errorf("fail to stat token file %s: %s", t.file, err)
+	}
+
+	t.lock.RLock()
+	changed := !info.ModTime().Equal(t.modTime)
+	t.lock.RUnlock()
+	if !changed && !force {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(t.file)
+	if err != nil {
+		return false, fmt.Errorf("fail to read token file %s: %s", t.file, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return false, fmt.Errorf("token file %s is empty", t.file)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.value = value
+	t.modTime = info.ModTime()
+	return true, nil
+}
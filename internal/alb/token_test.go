@@ -0,0 +1,104 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenFileReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("Token old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	token, err := newApiToken("", file)
+	if err != nil {
+		t.Fatalf("newApiToken: %s", err)
+	}
+	if v := token.get(); v != "Token old" {
+		t.Fatalf("token %q, want %q", v, "Token old")
+	}
+
+	if reloaded, err := token.reloadFile(false); err != nil || reloaded {
+		t.Errorf("unchanged file reloaded %v, err %v", reloaded, err)
+	}
+
+	if err := os.WriteFile(file, []byte("Token new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// the mod time may not change within its granularity, which is covered by force
+	if reloaded, err := token.reloadFile(true); err != nil || !reloaded {
+		t.Errorf("forced reload %v, err %v", reloaded, err)
+	}
+	if v := token.get(); v != "Token new" {
+		t.Errorf("token %q after reload, want %q", v, "Token new")
+	}
+
+	// a partially written file keeps the current token
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.reloadFile(true); err == nil {
+		t.Errorf("empty token file should fail to reload")
+	}
+	if v := token.get(); v != "Token new" {
+		t.Errorf("token %q after failed reload, want %q", v, "Token new")
+	}
+}
+
+func TestTokenRejected(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token valid" {
+			io.WriteString(w, `{"ErrNum":401,"ErrMsg":"Unauthorized"}`)
+			return
+		}
+		io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK"}`)
+	})
+
+	if err := c.Ping(context.Background()); !IsUnauthorized(err) {
+		t.Fatalf("Ping with invalid token should be Unauthorized, got %v", err)
+	}
+	select {
+	case <-c.token.rejected:
+	case <-time.After(time.Second):
+		t.Fatalf("rejected token not notified")
+	}
+
+	// notifications are coalesced, requests never block on them
+	c.Ping(context.Background())
+	c.Ping(context.Background())
+	<-c.token.rejected
+	select {
+	case <-c.token.rejected:
+		t.Errorf("rejections should be coalesced into one pending notification")
+	default:
+	}
+
+	c.token.set("Token valid")
+	if err := c.Ping(context.Background()); err != nil {
+		t.Errorf("Ping with valid token: %s", err)
+	}
+	select {
+	case <-c.token.rejected:
+		t.Errorf("accepted token notified as rejected")
+	default:
+	}
+}
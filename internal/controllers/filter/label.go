@@ -22,17 +22,26 @@ import (
 
 const (
 	BfenetworksAnnotationPrefix = "k8s.bfenetworks.com/"
+	ProductLabelKey             = "bfe-product"
 )
 
-func isBfenetworksTargetService(service client.Object) bool {
-	sname := service.GetName()
-	labels := service.GetLabels()
+// HasProductLabel checks whether the object has the bfe-product label
+func HasProductLabel(obj client.Object) bool {
+	labels := obj.GetLabels()
 	if labels != nil {
-		_, ipok := labels["bfe-product"]
+		_, ipok := labels[ProductLabelKey]
 		if ipok {
 			return true
 		}
 	}
+	return false
+}
+
+func isBfenetworksTargetService(service client.Object) bool {
+	sname := service.GetName()
+	if HasProductLabel(service) {
+		return true
+	}
 	util.HdlLogger.Info("bfe-product label does not present for k8s service", "sname", sname)
 
 	return false
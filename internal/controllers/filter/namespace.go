@@ -22,19 +22,24 @@ import (
 	"github.com/bfenetworks/service-controller/internal/option"
 )
 
-func NamespaceFilter() predicate.Funcs {
-	funcs := predicate.NewPredicateFuncs(func(obj client.Object) bool {
-		if len(option.Opts.NamespaceList) == 1 {
-			if option.Opts.NamespaceList[0] == corev1.NamespaceAll || option.Opts.NamespaceList[0] == "*" {
-				return true
-			}
+// IsWatchedNamespace checks whether the namespace is in the namespace list to watch
+func IsWatchedNamespace(namespace string) bool {
+	if len(option.Opts.NamespaceList) == 1 {
+		if option.Opts.NamespaceList[0] == corev1.NamespaceAll || option.Opts.NamespaceList[0] == "*" {
+			return true
 		}
-		for _, ns := range option.Opts.NamespaceList {
-			if ns == obj.GetNamespace() {
-				return true
-			}
+	}
+	for _, ns := range option.Opts.NamespaceList {
+		if ns == namespace {
+			return true
 		}
-		return false
+	}
+	return false
+}
+
+func NamespaceFilter() predicate.Funcs {
+	funcs := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return IsWatchedNamespace(obj.GetNamespace())
 	})
 
 	return funcs
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+	"github.com/bfenetworks/service-controller/internal/metrics"
+)
+
+// IgnoreSelfUpdate filters out the update events whose changes are confined to
+// the given finalizer, the given annotations and status, which are written by the controller.
+func IgnoreSelfUpdate(finalizer string, annotationKeys ...string) predicate.Funcs {
+	return IgnoreSelfUpdateWithRemovals(finalizer, nil, annotationKeys...)
+}
+
+// IgnoreSelfUpdateWithRemovals is IgnoreSelfUpdate, and also filters out the removal of removedKeys,
+// the annotations set by users and removed by the controller once used. Setting them is not filtered out.
+func IgnoreSelfUpdateWithRemovals(finalizer string, removedKeys []string, annotationKeys ...string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+
+			oldKeys := annotationKeys
+			for _, key := range removedKeys {
+				if _, ok := e.ObjectNew.GetAnnotations()[key]; !ok {
+					oldKeys = append(oldKeys[:len(oldKeys):len(oldKeys)], key)
+				}
+			}
+			oldObj, _ := stripSelfFields(e.ObjectOld, finalizer, oldKeys)
+			newObj, kind := stripSelfFields(e.ObjectNew, finalizer, annotationKeys)
+			if oldObj == nil || newObj == nil || !equality.Semantic.DeepEqual(oldObj, newObj) {
+				return true
+			}
+
+			metrics.SelfUpdatesIgnored.WithLabelValues(kind).Inc()
+			return false
+		},
+	}
+}
+
+// stripSelfFields returns a copy of obj without the fields written by the controller and its kind,
+// or nil if the kind is unknown.
+func stripSelfFields(obj client.Object, finalizer string, annotationKeys []string) (client.Object, string) {
+	var copied client.Object
+	var kind string
+	switch o := obj.(type) {
+	case *corev1.Service:
+		svc := o.DeepCopy()
+		svc.Status = corev1.ServiceStatus{}
+		copied, kind = svc, "Service"
+	case *v1alpha1.BfeServiceBinding:
+		binding := o.DeepCopy()
+		binding.Status = v1alpha1.BfeServiceBindingStatus{}
+		copied, kind = binding, "BfeServiceBinding"
+	default:
+		return nil, ""
+	}
+
+	copied.SetResourceVersion("")
+	copied.SetManagedFields(nil)
+
+	annotations := copied.GetAnnotations()
+	for _, key := range annotationKeys {
+		delete(annotations, key)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	copied.SetAnnotations(annotations)
+
+	finalizers := make([]string, 0, len(copied.GetFinalizers()))
+	for _, f := range copied.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == 0 {
+		finalizers = nil
+	}
+	copied.SetFinalizers(finalizers)
+
+	return copied, kind
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+)
+
+const (
+	testFinalizer = "k8s.bfenetworks.com/delete-protection"
+	testResult    = "k8s.bfenetworks.com/productpool-result"
+	testApprove   = "k8s.bfenetworks.com/approve-shrink"
+)
+
+func testService(mutate func(svc *corev1.Service)) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "web",
+			ResourceVersion: "1",
+			Labels:          map[string]string{"bfe-product": "demo"},
+			Annotations:     map[string]string{"user": "value"},
+		},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+	}
+	if mutate != nil {
+		mutate(svc)
+	}
+	return svc
+}
+
+func TestIgnoreSelfUpdate(t *testing.T) {
+	pred := IgnoreSelfUpdateWithRemovals(testFinalizer, []string{testApprove}, testResult)
+
+	cases := []struct {
+		name string
+		old  func(svc *corev1.Service)
+		new  func(svc *corev1.Service)
+		want bool
+	}{
+		{
+			name: "status",
+			new: func(svc *corev1.Service) {
+				svc.ResourceVersion = "2"
+				svc.Status.Conditions = []metav1.Condition{{Type: "BfeSynced"}}
+			},
+			want: false,
+		},
+		{
+			name: "finalizer",
+			new:  func(svc *corev1.Service) { svc.Finalizers = []string{testFinalizer} },
+			want: false,
+		},
+		{
+			name: "result annotation",
+			new:  func(svc *corev1.Service) { svc.Annotations[testResult] = "[]" },
+			want: false,
+		},
+		{
+			name: "approval removed",
+			old:  func(svc *corev1.Service) { svc.Annotations[testApprove] = "true" },
+			want: false,
+		},
+		{
+			name: "approval removed with the last annotation",
+			old:  func(svc *corev1.Service) { svc.Annotations = map[string]string{testApprove: "true"} },
+			new:  func(svc *corev1.Service) { svc.Annotations = nil },
+			want: false,
+		},
+		{
+			name: "approval set",
+			new:  func(svc *corev1.Service) { svc.Annotations[testApprove] = "true" },
+			want: true,
+		},
+		{
+			name: "approval changed",
+			old:  func(svc *corev1.Service) { svc.Annotations[testApprove] = "false" },
+			new:  func(svc *corev1.Service) { svc.Annotations[testApprove] = "true" },
+			want: true,
+		},
+		{
+			name: "approval removed with other changes",
+			old:  func(svc *corev1.Service) { svc.Annotations[testApprove] = "true" },
+			new:  func(svc *corev1.Service) { svc.Labels["bfe-product"] = "other" },
+			want: true,
+		},
+		{
+			name: "user annotation",
+			new:  func(svc *corev1.Service) { svc.Annotations["user"] = "changed" },
+			want: true,
+		},
+		{
+			name: "spec",
+			new:  func(svc *corev1.Service) { svc.Spec.Ports[0].Port = 8080 },
+			want: true,
+		},
+	}
+	for _, tc := range cases {
+		e := event.UpdateEvent{ObjectOld: testService(tc.old), ObjectNew: testService(tc.new)}
+		if got := pred.Update(e); got != tc.want {
+			t.Errorf("%s: Update() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestIgnoreSelfUpdateBinding(t *testing.T) {
+	pred := IgnoreSelfUpdate(testFinalizer, testResult)
+	binding := &v1alpha1.BfeServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", Annotations: map[string]string{testApprove: "true"}},
+		Spec:       v1alpha1.BfeServiceBindingSpec{ServiceName: "web", Products: []string{"demo"}},
+	}
+
+	updated := binding.DeepCopy()
+	updated.Status.Pools = []v1alpha1.BoundPoolStatus{{Product: "demo", Name: "demo.web"}}
+	if pred.Update(event.UpdateEvent{ObjectOld: binding, ObjectNew: updated}) {
+		t.Errorf("status update of binding should be ignored")
+	}
+
+	// not given as removed by the controller
+	updated = binding.DeepCopy()
+	delete(updated.Annotations, testApprove)
+	if !pred.Update(event.UpdateEvent{ObjectOld: binding, ObjectNew: updated}) {
+		t.Errorf("removing an annotation not written by the controller should pass")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+)
+
+// adoptedPool is an existing BFE pool to be taken over, parsed from AdoptPoolAnnotationKey
+type adoptedPool struct {
+	Product string
+	Pool    string
+	Port    string // port name of the service, can be omitted if the service has one named port
+}
+
+// adoptedPools parses the annotation of obj in the format of "<product>/<pool>[:<port>],...",
+// every product must be one of products.
+func adoptedPools(obj client.Object, products []string) ([]adoptedPool, error) {
+	value := strings.TrimSpace(obj.GetAnnotations()[AdoptPoolAnnotationKey])
+	if value == "" {
+		return nil, nil
+	}
+
+	adopted := make([]adoptedPool, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		product, pool, ok := strings.Cut(item, "/")
+		if !ok || product == "" || pool == "" {
+			return nil, fmt.Errorf("invalid annotation %s: %q, should be <product>/<pool>[:<port>]", AdoptPoolAnnotationKey, item)
+		}
+		pool, port, _ := strings.Cut(pool, ":")
+		if !containsString(products, product) {
+			return nil, fmt.Errorf("invalid annotation %s: %q, product %s is not published", AdoptPoolAnnotationKey, item, product)
+		}
+		if !strings.HasPrefix(pool, product+".") {
+			pool = product + "." + pool
+		}
+
+		adopted = append(adopted, adoptedPool{Product: product, Pool: pool, Port: port})
+	}
+
+	return adopted, nil
+}
+
+// applyAdoption replaces the pool names of bindings of product by the adopted pools,
+// and allows them to take over the pools not managed by any controller.
+func applyAdoption(adopted []adoptedPool, product string, bindings []openapi.PoolBinding) ([]openapi.PoolBinding, error) {
+	for _, a := range adopted {
+		if a.Product != product {
+			continue
+		}
+
+		idx := -1
+		for i, b := range bindings {
+			if (a.Port != "" && b.PortName == a.Port) || (a.Port == "" && b.PoolName == a.Pool) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 && a.Port == "" {
+			if len(bindings) != 1 {
+				return nil, fmt.Errorf("port of adopted pool %s must be specified, service has %d named ports", a.Pool, len(bindings))
+			}
+			idx = 0
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("port %s of adopted pool %s is not a named port of service", a.Port, a.Pool)
+		}
+
+		bindings[idx].PoolName = a.Pool
+		bindings[idx].Adopt = true
+	}
+
+	return bindings, nil
+}
+
+// genAdoptedPoolNameList returns the adopted pools in bindings of product
+func genAdoptedPoolNameList(product string, bindings []openapi.PoolBinding) openapi.ProductPoolnameList {
+	adopted := make(openapi.ProductPoolnameList, 0)
+	for _, b := range bindings {
+		if b.Adopt {
+			adopted = append(adopted, openapi.ProductPoolname{Product: product, Poolname: b.PoolName})
+		}
+	}
+	return adopted
+}
+
+// adoptedPoolList returns the adopted pools recorded in the annotation of obj
+func adoptedPoolList(obj client.Object) openapi.ProductPoolnameList {
+	annotation := obj.GetAnnotations()[AdoptedPoolsAnnotationKey]
+	if annotation == "" {
+		return nil
+	}
+	poollist, err := extractPoolList(annotation)
+	if err != nil {
+		return nil
+	}
+	return poollist
+}
+
+// servicePoolBindings returns the pool bindings of a service published by the label
+func servicePoolBindings(svc *corev1.Service, product string) ([]openapi.PoolBinding, error) {
+	adopted, err := adoptedPools(svc, []string{product})
+	if err != nil {
+		return nil, err
+	}
+	bindings := applyClusterWeight(svc, openapi.DefaultPoolBindings(product, svc, poolClusterName(svc)))
+	return applyAdoption(adopted, product, bindings)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	"github.com/bfenetworks/service-controller/internal/controllers/filter"
+	"github.com/bfenetworks/service-controller/internal/option"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+const (
+	bindingServiceIndex = ".spec.serviceName"
+
+	// interval of checking whether the bindings of a service have written their pools
+	handOverInterval = 30 * time.Second
+)
+
+func AddBindingController(mgr manager.Manager, provider *openapi.AlbProvider) error {
+	reconciler := newBindingReconciler(mgr, provider)
+	if err := reconciler.setupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create service binding controller for loadbalancer: %s", err)
+	}
+
+	return nil
+}
+
+// BindingReconciler reconciles a BfeServiceBinding object
+type BindingReconciler struct {
+	poolReconciler
+}
+
+func newBindingReconciler(mgr manager.Manager, provider *openapi.AlbProvider) *BindingReconciler {
+	return &BindingReconciler{
+		poolReconciler: newPoolReconciler(mgr, provider),
+	}
+}
+
+func (r *BindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	binding := &v1alpha1.BfeServiceBinding{}
+	if err := r.Get(ctx, req.NamespacedName, binding); err != nil {
+		// deleted binding has been handled before finalizer removed
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	isdel := binding.DeletionTimestamp != nil && !binding.DeletionTimestamp.IsZero()
+	util.K8sCLogger.Info("reconciling service binding", "namespace", req.Namespace, "name", req.Name, "isdel", isdel)
+
+	if isPaused(binding) {
+		return r.reconcilePaused(ctx, req, binding, isdel)
+	}
+
+	var err error
+	if isdel {
+		err = r.deletePool(ctx, binding, strings.ToLower(binding.GetDeletionPolicy()))
+		if err == nil || option.Opts.ForceRmFinalizer {
+			r.removeFinalizer(ctx, binding)
+		}
+		r.emitEvent(binding, err, req.Namespace, req.Name, OPTypeDelete)
+	} else {
+		if !hasFinalizer(binding, FinalizerName) {
+			if err = r.addFinalizer(ctx, binding); err != nil {
+				util.K8sCLogger.Info("reconciling service binding failed to add finalizer", "namespace", req.Namespace, "name", req.Name)
+				return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, err
+			}
+		}
+
+		var pools []openapi.PoolStatus
+		var bound []v1alpha1.BoundPoolStatus
+		pools, bound, err = r.ensureBinding(ctx, binding)
+		if err == nil && needResync(binding) {
+			r.markResynced(ctx, binding)
+		}
+		if err == nil {
+			r.consumeShrinkApproval(ctx, binding, pools)
+		}
+		r.emitEvent(binding, err, req.Namespace, req.Name, OPTypeUpdate)
+		r.updateStatus(ctx, binding, pools, bound, err)
+	}
+
+	var serr *ServiceNotFoundError
+	if errors.As(err, &serr) {
+		// reconciled again when the service is created
+		util.HdlLogger.Info("service of binding not found, wait for it", "namespace", req.Namespace, "name", req.Name, "service", serr.Service)
+		return ctrl.Result{}, nil
+	}
+	if err != nil && option.Opts.RetryIntervalUnitForErrS > 0 {
+		util.HdlLogger.Error(err, "reconciling service binding error, will retry...")
+		return ctrl.Result{RequeueAfter: time.Duration(option.Opts.RetryIntervalUnitForErrS) * time.Second}, nil
+	}
+	if err == nil && !isdel {
+		return sharedPoolResync(binding), nil
+	}
+
+	return ctrl.Result{}, err
+}
+
+// ensureBinding writes the pools declared by binding for every product
+func (r *BindingReconciler) ensureBinding(ctx context.Context, binding *v1alpha1.BfeServiceBinding) (
+	[]openapi.PoolStatus, []v1alpha1.BoundPoolStatus, error) {
+	key := client.ObjectKey{Namespace: binding.Namespace, Name: binding.Spec.ServiceName}
+
+	svc, err := r.bindingService(ctx, binding)
+	if err != nil {
+		return nil, nil, err
+	}
+	ep := &corev1.Endpoints{}
+	if err := r.Get(ctx, key, ep); err != nil {
+		return nil, nil, fmt.Errorf("fail to get endpoints %s: %s", key, err)
+	}
+
+	adopted, err := adoptedPools(binding, binding.Spec.Products)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := ensureOptions(binding, svc)
+	all := make([]openapi.PoolStatus, 0)
+	bound := make([]v1alpha1.BoundPoolStatus, 0)
+	newpools := make(openapi.ProductPoolnameList, 0)
+	adoptedpools := make(openapi.ProductPoolnameList, 0)
+	owner := poolOwnerOf(binding)
+	for _, product := range binding.Spec.Products {
+		var bindings []openapi.PoolBinding
+		bindings, err = applyAdoption(adopted, product, genPoolBindings(binding, svc, product))
+		if err != nil {
+			break
+		}
+		if err = owners.claim(ctx, r.Client, owner, genPoolNameListByBindings(product, bindings)); err != nil {
+			break
+		}
+		adoptedpools = append(adoptedpools, genAdoptedPoolNameList(product, bindings)...)
+
+		var pools []openapi.PoolStatus
+		pools, err = r.ExternalLB.EnsureProductPoolByBindings(ctx, product, ep, bindings, opts)
+
+		all = append(all, pools...)
+		newpools = append(newpools, genProductPoolNameList(product, pools)...)
+		for _, p := range pools {
+			bound = append(bound, v1alpha1.BoundPoolStatus{
+				Product:   product,
+				Name:      p.Name,
+				Port:      p.Port,
+				Instances: p.Instances,
+			})
+		}
+		if err != nil {
+			break
+		}
+	}
+	r.recordPools(ctx, binding, newpools, adoptedpools, err)
+
+	return all, bound, err
+}
+
+func genPoolBindings(binding *v1alpha1.BfeServiceBinding, svc *corev1.Service, product string) []openapi.PoolBinding {
+	ports := binding.Spec.Ports
+	if len(ports) == 0 {
+		for _, port := range svc.Spec.Ports {
+			if port.Name != "" {
+				ports = append(ports, v1alpha1.PortBinding{Name: port.Name})
+			}
+		}
+	}
+
+	bindings := make([]openapi.PoolBinding, 0, len(ports))
+	for _, port := range ports {
+		name := port.PoolName
+		if name == "" {
+			name = openapi.PoolName(product, svc.Namespace, svc.Name, port.Name, poolClusterName(binding))
+		} else if !strings.HasPrefix(name, product+".") {
+			name = product + "." + name
+		}
+
+		bindings = append(bindings, openapi.PoolBinding{
+			PortName:        port.Name,
+			PoolName:        name,
+			Weight:          binding.GetWeight(),
+			IncludeNotReady: binding.Spec.WeightPolicy.Type == v1alpha1.WeightPolicyDrainNotReady,
+		})
+	}
+	return applyClusterWeight(svc, bindings)
+}
+
+func (r *BindingReconciler) updateStatus(ctx context.Context, binding *v1alpha1.BfeServiceBinding,
+	pools []openapi.PoolStatus, bound []v1alpha1.BoundPoolStatus, err error) error {
+	patch := client.MergeFrom(binding.DeepCopy())
+
+	binding.Status.ObservedGeneration = binding.Generation
+	if err == nil || len(bound) > 0 {
+		binding.Status.Pools = bound
+	}
+	if resyncAt := binding.Annotations[ResyncProcessedAnnotationKey]; resyncAt != "" {
+		binding.Status.LastResyncAt = resyncAt
+	}
+	for _, cond := range genConditions(binding.Generation, pools, err, OPTypeUpdate) {
+		meta.SetStatusCondition(&binding.Status.Conditions, cond)
+	}
+
+	if terr := r.Status().Patch(ctx, binding, patch); terr != nil {
+		util.HdlLogger.Error(terr, "fail to patch service binding status", "namespace", binding.Namespace, "name", binding.Name)
+		return terr
+	}
+
+	return nil
+}
+
+// ServiceNotFoundError is reported on a binding whose service does not exist
+type ServiceNotFoundError struct {
+	Service string
+}
+
+func (e *ServiceNotFoundError) Error() string {
+	return fmt.Sprintf("service %s of the binding is not found", e.Service)
+}
+
+// bindingService returns the service published by binding, ServiceNotFoundError if it does not exist
+func (r *poolReconciler) bindingService(ctx context.Context, binding *v1alpha1.BfeServiceBinding) (*corev1.Service, error) {
+	key := client.ObjectKey{Namespace: binding.Namespace, Name: binding.Spec.ServiceName}
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, key, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &ServiceNotFoundError{Service: key.String()}
+		}
+		return nil, fmt.Errorf("fail to get service %s: %s", key, err)
+	}
+	return svc, nil
+}
+
+// bindingServiceName indexes a binding by the name of its service
+func bindingServiceName(obj client.Object) []string {
+	return []string{obj.(*v1alpha1.BfeServiceBinding).Spec.ServiceName}
+}
+
+// serviceBindings returns the BfeServiceBindings referring to the service
+func serviceBindings(ctx context.Context, c client.Reader, svc *corev1.Service) []v1alpha1.BfeServiceBinding {
+	bindings := &v1alpha1.BfeServiceBindingList{}
+	err := c.List(ctx, bindings, client.InNamespace(svc.Namespace), client.MatchingFields{bindingServiceIndex: svc.Name})
+	if err != nil {
+		util.K8sCLogger.Error(err, "fail to list service bindings", "namespace", svc.Namespace, "name", svc.Name)
+		return nil
+	}
+
+	return bindings.Items
+}
+
+// handOverPools hands the pools written by the label of svc over to its bindings. The pools also
+// written by the bindings are no longer recorded on svc, and the others are deleted as the label
+// stops publishing them, or released if adopted. It returns false if a binding has not written its pools yet.
+func (r *poolReconciler) handOverPools(ctx context.Context, svc *corev1.Service, bindings []v1alpha1.BfeServiceBinding) (bool, error) {
+	annotation := svc.Annotations[ProductPoolResultAnnotationKey]
+	if annotation == "" {
+		return true, nil
+	}
+	oldpools, err := extractPoolList(annotation)
+	if err != nil {
+		return true, nil
+	}
+
+	bound := make(openapi.ProductPoolnameList, 0)
+	for i := range bindings {
+		value := bindings[i].Annotations[ProductPoolResultAnnotationKey]
+		if value == "" {
+			return false, nil
+		}
+		pools, err := extractPoolList(value)
+		if err != nil {
+			return false, nil
+		}
+		bound = append(bound, pools...)
+	}
+
+	orphans := diffList(oldpools, bound)
+	released := intersectList(orphans, adoptedPoolList(svc))
+	orphans = diffList(orphans, released)
+	unreleased, err := r.releasePools(ctx, svc, released)
+	if err != nil {
+		return true, err
+	}
+	delnames, err := r.ExternalLB.DeleteProductPoolByList(ctx, orphans, poolOwnership(svc, svc))
+	remain := append(diffList(orphans, delnames), unreleased...)
+	util.HdlLogger.Info("hand over pools to service bindings", "namespace", svc.Namespace, "name", svc.Name,
+		"deleted", len(delnames), "released", len(released)-len(unreleased), "remain", len(remain))
+	if terr := r.addAnnotationByList(ctx, svc, remain, ProductPoolResultAnnotationKey); terr != nil && err == nil {
+		err = terr
+	}
+	if terr := r.recordAdoptedPools(ctx, svc, intersectList(adoptedPoolList(svc), remain)); terr != nil && err == nil {
+		err = terr
+	}
+	return true, err
+}
+
+// bindingsOfObject maps a Service or Endpoints to the bindings referring to it
+func (r *BindingReconciler) bindingsOfObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	bindings := &v1alpha1.BfeServiceBindingList{}
+	err := r.List(ctx, bindings, client.InNamespace(obj.GetNamespace()), client.MatchingFields{bindingServiceIndex: obj.GetName()})
+	if err != nil {
+		util.K8sCLogger.Error(err, "fail to list service bindings", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, 0, len(bindings.Items))
+	for i := range bindings.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&bindings.Items[i])})
+	}
+	return reqs
+}
+
+func (r *BindingReconciler) setupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.BfeServiceBinding{}, bindingServiceIndex, bindingServiceName)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.BfeServiceBinding{}, builder.WithPredicates(filter.NamespaceFilter(), ignoreSelfUpdate())).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.bindingsOfObject),
+			builder.WithPredicates(filter.NamespaceFilter(), ignoreSelfUpdate()),
+		).
+		Watches(
+			&corev1.Endpoints{},
+			newDebounceHandler(mgr.GetClient(), r.bindingsOfObject),
+			builder.WithPredicates(filter.NamespaceFilter()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+	"github.com/bfenetworks/service-controller/internal/option"
+)
+
+// emptyPoolBFE replies every pool as an empty one
+func emptyPoolBFE(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"demo.label_pool","instances":[]}}`)
+		return
+	}
+	io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK"}`)
+}
+
+// boundService returns a service published by the label, whose pool is not written by its binding
+func boundService(paused bool) (*corev1.Service, *v1alpha1.BfeServiceBinding) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web",
+			UID:       "uid-web",
+			Labels:    map[string]string{"bfe-product": "demo"},
+			Annotations: map[string]string{
+				ProductPoolResultAnnotationKey: `[{"Product":"demo","Poolname":"demo.label_pool"}]`,
+			},
+		},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+	}
+	if paused {
+		svc.Annotations[PausedAnnotationKey] = "true"
+	}
+	binding := &v1alpha1.BfeServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web",
+			Annotations: map[string]string{
+				ProductPoolResultAnnotationKey: `[{"Product":"demo","Poolname":"demo.binding_pool"}]`,
+			},
+		},
+		Spec: v1alpha1.BfeServiceBindingSpec{ServiceName: "web", Products: []string{"demo"}},
+	}
+	return svc, binding
+}
+
+func TestHandOverPaused(t *testing.T) {
+	for _, paused := range []bool{true, false} {
+		bfe := newFakeBFE(t, emptyPoolBFE)
+		svc, binding := boundService(paused)
+		r := &ServiceReconciler{poolReconciler: newTestReconciler(t, bfe, svc, binding)}
+		option.Opts.EnableServiceBinding = true
+
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}}
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile: %s", err)
+		}
+
+		want := 1
+		if paused {
+			want = 0
+		}
+		if n := bfe.count(http.MethodDelete); n != want {
+			t.Errorf("paused %v: %d DELETE sent on handing over, want %d", paused, n, want)
+		}
+	}
+}
+
+func TestBindingServiceNotFound(t *testing.T) {
+	bfe := newFakeBFE(t, nil)
+	_, binding := boundService(false)
+	r := &BindingReconciler{poolReconciler: newTestReconciler(t, bfe, binding)}
+	option.Opts.EnableServiceBinding = true
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}}
+	res, err := r.Reconcile(ctx, req)
+	if err != nil || res.Requeue || res.RequeueAfter != 0 {
+		t.Fatalf("binding without service should wait for the service instead of retrying, got %v, %v", res, err)
+	}
+
+	cur := &v1alpha1.BfeServiceBinding{}
+	if err := r.Get(ctx, req.NamespacedName, cur); err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	cond := meta.FindStatusCondition(cur.Status.Conditions, ConditionBfeSynced)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReasonNoService {
+		t.Errorf("condition %s should report the missing service, got %v", ConditionBfeSynced, cond)
+	}
+	if n := len(bfe.requests); n != 0 {
+		t.Errorf("%d requests sent to BFE for a binding without service", n)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	"github.com/bfenetworks/service-controller/internal/option"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+const (
+	// maxInstanceWeight is the max weight of an instance accepted by BFE
+	maxInstanceWeight = 100
+)
+
+// clusterWeight returns the weight factor of the instances of svc in this cluster, by annotation or the default
+func clusterWeight(svc *corev1.Service) int64 {
+	weight := int64(option.Opts.ClusterWeight)
+
+	value, ok := svc.GetAnnotations()[ClusterWeightAnnotationKey]
+	if !ok {
+		return weight
+	}
+	w, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || w < 0 || w > maxInstanceWeight {
+		util.HdlLogger.Info("invalid cluster weight annotation, use default", "namespace", svc.Namespace, "name", svc.Name,
+			"value", value)
+		return weight
+	}
+	return w
+}
+
+// applyClusterWeight multiplies the instance weight of bindings by the cluster weight of svc
+func applyClusterWeight(svc *corev1.Service, bindings []openapi.PoolBinding) []openapi.PoolBinding {
+	factor := clusterWeight(svc)
+	for i := range bindings {
+		weight := bindings[i].Weight * factor
+		if weight > maxInstanceWeight {
+			weight = maxInstanceWeight
+		}
+		bindings[i].Weight = weight
+	}
+	return bindings
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	"github.com/bfenetworks/service-controller/internal/option"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// pendingRequest is a reconcile request delayed by debounceHandler
+type pendingRequest struct {
+	first    time.Time
+	last     time.Time
+	window   time.Duration
+	maxDelay time.Duration
+}
+
+// debounceHandler delays the reconcile requests caused by endpoints changes, so that a burst of
+// changes is merged into one reconcile. A request is enqueued when no change comes within the
+// debounce window, or at the latest max-delay after the first change.
+type debounceHandler struct {
+	reader  client.Reader
+	mapFunc handler.MapFunc
+
+	lock    sync.Mutex
+	pending map[reconcile.Request]*pendingRequest
+}
+
+var _ handler.EventHandler = &debounceHandler{}
+
+func newDebounceHandler(reader client.Reader, mapFunc handler.MapFunc) *debounceHandler {
+	return &debounceHandler{
+		reader:  reader,
+		mapFunc: mapFunc,
+		pending: make(map[reconcile.Request]*pendingRequest),
+	}
+}
+
+// enqueueEndpoints maps endpoints to the request of the service with the same name
+func enqueueEndpoints(_ context.Context, obj client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(obj)}}
+}
+
+func (h *debounceHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.debounce(ctx, e.Object, q)
+}
+
+func (h *debounceHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.debounce(ctx, e.ObjectNew, q)
+}
+
+func (h *debounceHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	for _, req := range h.mapFunc(ctx, e.Object) {
+		q.Add(req)
+	}
+}
+
+func (h *debounceHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	for _, req := range h.mapFunc(ctx, e.Object) {
+		q.Add(req)
+	}
+}
+
+func (h *debounceHandler) debounce(ctx context.Context, obj client.Object, q workqueue.RateLimitingInterface) {
+	metrics.EndpointsEvents.Inc()
+
+	window, maxDelay := h.windowOf(ctx, obj)
+	for _, req := range h.mapFunc(ctx, obj) {
+		if window <= 0 {
+			q.Add(req)
+			continue
+		}
+		h.delay(req, q, window, maxDelay)
+	}
+}
+
+// windowOf returns the debounce window and max delay of the service of the endpoints
+func (h *debounceHandler) windowOf(ctx context.Context, obj client.Object) (time.Duration, time.Duration) {
+	window := time.Duration(option.Opts.EndpointsDebounceMs) * time.Millisecond
+	maxDelay := time.Duration(option.Opts.EndpointsMaxDelayMs) * time.Millisecond
+
+	svc := &corev1.Service{}
+	if err := h.reader.Get(ctx, client.ObjectKeyFromObject(obj), svc); err == nil {
+		window = durationAnnotation(svc, EndpointsDebounceAnnotationKey, window)
+		maxDelay = durationAnnotation(svc, EndpointsMaxDelayAnnotationKey, maxDelay)
+	}
+	if maxDelay < window {
+		maxDelay = window
+	}
+
+	return window, maxDelay
+}
+
+func durationAnnotation(obj client.Object, key string, defaultValue time.Duration) time.Duration {
+	value, ok := obj.GetAnnotations()[key]
+	if !ok {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		util.HdlLogger.Info("invalid duration annotation, use default", "namespace", obj.GetNamespace(), "name", obj.GetName(),
+			"key", key, "value", value)
+		return defaultValue
+	}
+	return d
+}
+
+func (h *debounceHandler) delay(req reconcile.Request, q workqueue.RateLimitingInterface, window, maxDelay time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	now := time.Now()
+	if p, ok := h.pending[req]; ok {
+		// merged into the pending request
+		p.last = now
+		metrics.EndpointsEventsCoalesced.Inc()
+		return
+	}
+
+	h.pending[req] = &pendingRequest{
+		first:    now,
+		last:     now,
+		window:   window,
+		maxDelay: maxDelay,
+	}
+	time.AfterFunc(window, func() { h.fire(req, q) })
+}
+
+func (h *debounceHandler) fire(req reconcile.Request, q workqueue.RateLimitingInterface) {
+	h.lock.Lock()
+	p, ok := h.pending[req]
+	if !ok {
+		h.lock.Unlock()
+		return
+	}
+
+	now := time.Now()
+	next := p.last.Add(p.window)
+	deadline := p.first.Add(p.maxDelay)
+	if now.Before(next) && now.Before(deadline) {
+		// changes still coming, wait for more
+		if deadline.Before(next) {
+			next = deadline
+		}
+		time.AfterFunc(next.Sub(now), func() { h.fire(req, q) })
+		h.lock.Unlock()
+		return
+	}
+	delete(h.pending, req)
+	h.lock.Unlock()
+
+	metrics.EndpointsPropagationDelaySeconds.Observe(now.Sub(p.first).Seconds())
+	q.Add(req)
+}
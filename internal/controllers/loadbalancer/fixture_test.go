@@ -0,0 +1,105 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	"github.com/bfenetworks/service-controller/internal/option"
+)
+
+// fakeBFE is a fake BFE api server recording the requests it got
+type fakeBFE struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []string // "<method> <path>"
+}
+
+func newFakeBFE(t *testing.T, handler http.HandlerFunc) *fakeBFE {
+	f := &fakeBFE{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.lock.Lock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		f.lock.Unlock()
+
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		io.WriteString(w, `{"ErrNum":200,"ErrMsg":"OK"}`)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// count returns the number of requests of method
+func (f *fakeBFE) count(method string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	n := 0
+	for _, req := range f.requests {
+		if len(req) > len(method) && req[:len(method)+1] == method+" " {
+			n++
+		}
+	}
+	return n
+}
+
+// newTestReconciler returns a reconciler of objs writing pools to bfe, with the default options
+func newTestReconciler(t *testing.T, bfe *fakeBFE, objs ...client.Object) poolReconciler {
+	option.Opts = option.NewOptions()
+	option.Opts.ExternalLB.ApiServerAddr = bfe.URL
+	option.Opts.ExternalLB.Token = "Token test"
+	option.Opts.ExternalLB.Retries = 0
+	option.Opts.ExternalLB.CircuitThreshold = 0
+	provider, err := openapi.NewAlbProvider(option.Opts.ExternalLB)
+	if err != nil {
+		t.Fatalf("NewAlbProvider: %s", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.BfeServiceBinding{}).
+		WithIndex(&v1alpha1.BfeServiceBinding{}, bindingServiceIndex, bindingServiceName).
+		Build()
+
+	return poolReconciler{
+		ExternalLB: provider,
+		Client:     c,
+		Scheme:     scheme,
+		recorder:   record.NewFakeRecorder(100),
+	}
+}
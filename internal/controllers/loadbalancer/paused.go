@@ -0,0 +1,144 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	"github.com/bfenetworks/service-controller/internal/controllers/filter"
+	"github.com/bfenetworks/service-controller/internal/controllers/readiness"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// pausedError is the result of a reconcile skipped because the object is paused,
+// it records what would have been done.
+type pausedError struct {
+	op   string
+	plan string
+}
+
+func (e *pausedError) Error() string {
+	return fmt.Sprintf("paused, would %s %s", e.op, e.plan)
+}
+
+func isPaused(obj client.Object) bool {
+	return strings.EqualFold(obj.GetAnnotations()[PausedAnnotationKey], "true")
+}
+
+func describePools(pools []openapi.PoolStatus) string {
+	items := make([]string, 0, len(pools))
+	for _, p := range pools {
+		items = append(items, fmt.Sprintf("%s(%d instances)", p.Name, p.Instances))
+	}
+	return "pools [" + strings.Join(items, ", ") + "]"
+}
+
+func describeRecordedPools(obj client.Object) string {
+	items := make([]string, 0)
+	if annotation := obj.GetAnnotations()[ProductPoolResultAnnotationKey]; annotation != "" {
+		if poollist, err := extractPoolList(annotation); err == nil {
+			for _, p := range poollist {
+				items = append(items, p.Poolname)
+			}
+		}
+	}
+	return "pools [" + strings.Join(items, ", ") + "]"
+}
+
+// reconcilePaused records what would be done for a paused service, without any BFE operation
+func (r *ServiceReconciler) reconcilePaused(ctx context.Context, req ctrl.Request, svc *corev1.Service, isdel bool) (ctrl.Result, error) {
+	perr := &pausedError{op: OPTypeUpdate}
+	op := OPTypeUpdate
+	if isdel {
+		// keep the finalizer until resumed
+		op = OPTypeDelete
+		perr.op = deletionPolicy(svc)
+		perr.plan = describeRecordedPools(svc)
+	} else {
+		ep := &corev1.Endpoints{}
+		if err := r.Get(ctx, req.NamespacedName, ep); err != nil {
+			return ctrl.Result{}, err
+		}
+		product := svc.Labels[filter.ProductLabelKey]
+		bindings, err := servicePoolBindings(svc, product)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		perr.plan = describePools(openapi.PlanProductPoolByBindings(ep, bindings))
+	}
+	util.HdlLogger.Info("service paused, skip bfe operation", "namespace", req.Namespace, "name", req.Name, "plan", perr.Error())
+
+	r.emitEvent(svc, perr, req.Namespace, req.Name, op)
+	r.handleResult(ctx, svc, req.Namespace, req.Name, nil, perr, op)
+	readiness.MarkSynced(req.NamespacedName.String())
+
+	return ctrl.Result{}, nil
+}
+
+// reconcilePaused records what would be done for a paused binding, without any BFE operation
+func (r *BindingReconciler) reconcilePaused(ctx context.Context, req ctrl.Request, binding *v1alpha1.BfeServiceBinding, isdel bool) (ctrl.Result, error) {
+	perr := &pausedError{op: OPTypeUpdate}
+	if isdel {
+		// keep the finalizer until resumed
+		perr.op = strings.ToLower(binding.GetDeletionPolicy())
+		perr.plan = describeRecordedPools(binding)
+		r.emitEvent(binding, perr, req.Namespace, req.Name, OPTypeDelete)
+		return ctrl.Result{}, nil
+	}
+
+	key := client.ObjectKey{Namespace: binding.Namespace, Name: binding.Spec.ServiceName}
+	svc, err := r.bindingService(ctx, binding)
+	var serr *ServiceNotFoundError
+	if errors.As(err, &serr) {
+		r.updateStatus(ctx, binding, nil, nil, err)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	ep := &corev1.Endpoints{}
+	if err := r.Get(ctx, key, ep); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	adopted, err := adoptedPools(binding, binding.Spec.Products)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	pools := make([]openapi.PoolStatus, 0)
+	for _, product := range binding.Spec.Products {
+		bindings, err := applyAdoption(adopted, product, genPoolBindings(binding, svc, product))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		pools = append(pools, openapi.PlanProductPoolByBindings(ep, bindings)...)
+	}
+	perr.plan = describePools(pools)
+	util.HdlLogger.Info("service binding paused, skip bfe operation", "namespace", req.Namespace, "name", req.Name, "plan", perr.Error())
+
+	r.emitEvent(binding, perr, req.Namespace, req.Name, OPTypeUpdate)
+	r.updateStatus(ctx, binding, nil, nil, perr)
+
+	return ctrl.Result{}, nil
+}
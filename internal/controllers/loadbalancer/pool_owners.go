@@ -0,0 +1,231 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bfenetworks/service-controller/api/v1alpha1"
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	"github.com/bfenetworks/service-controller/internal/option"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// PoolCollisionError is returned when a pool to write is already used by another service
+type PoolCollisionError struct {
+	Pool  openapi.ProductPoolname
+	Owner string
+}
+
+func (e *PoolCollisionError) Error() string {
+	return fmt.Sprintf("pool %s of product %s is already used by service %s, rename the port or pool to avoid collision",
+		e.Pool.Poolname, e.Pool.Product, e.Owner)
+}
+
+// poolOwners indexes the service owning every pool written by this controller,
+// to detect different services mapping to the same pool.
+// A service published by both the label and BfeServiceBinding is the same owner.
+type poolOwners struct {
+	lock   sync.Mutex
+	seeded bool
+	owners map[openapi.ProductPoolname]string
+}
+
+var (
+	owners = &poolOwners{
+		owners: make(map[openapi.ProductPoolname]string),
+	}
+)
+
+// ownerKey returns the owner of the pools published for a service
+func ownerKey(namespace, serviceName string) string {
+	return namespace + "/" + serviceName
+}
+
+// poolOwnerOf returns the owner of the pools recorded in obj
+func poolOwnerOf(obj client.Object) string {
+	if binding, ok := obj.(*v1alpha1.BfeServiceBinding); ok {
+		return ownerKey(binding.Namespace, binding.Spec.ServiceName)
+	}
+	return ownerKey(obj.GetNamespace(), obj.GetName())
+}
+
+// poolOwnership returns the ownership of the pools of obj, which publishes svc. svc is nil if it is gone,
+// its uid is compared only if the cluster name is empty. The pools recorded in obj and svc are regarded
+// as written for the service.
+func poolOwnership(obj client.Object, svc *corev1.Service) openapi.Ownership {
+	namespace := obj.GetNamespace()
+	name := obj.GetName()
+	if binding, ok := obj.(*v1alpha1.BfeServiceBinding); ok {
+		name = binding.Spec.ServiceName
+	}
+
+	var uid types.UID
+	recorded := recordedPools(obj)
+	if svc != nil {
+		uid = svc.UID
+		for pool := range recordedPools(svc) {
+			recorded[pool] = true
+		}
+	}
+
+	return openapi.Ownership{
+		Owner: openapi.PoolOwner{
+			ControllerID: option.Opts.ControllerID,
+			Cluster:      option.Opts.ClusterName,
+			Namespace:    namespace,
+			Name:         name,
+			UID:          string(uid),
+		},
+		Adopt:    option.Opts.AdoptUnownedPools,
+		Shared:   isSharedPool(obj),
+		Recorded: recorded,
+	}
+}
+
+// recordedPools returns the names of the pools recorded in the annotation of obj
+func recordedPools(obj client.Object) map[string]bool {
+	recorded := make(map[string]bool)
+	annotation := obj.GetAnnotations()[ProductPoolResultAnnotationKey]
+	if annotation == "" {
+		return recorded
+	}
+	poollist, err := extractPoolList(annotation)
+	if err != nil {
+		return recorded
+	}
+	for _, p := range poollist {
+		recorded[p.Poolname] = true
+	}
+	return recorded
+}
+
+// ownershipOf returns the ownership of the pools recorded in obj, with the uid of the service if it exists
+func (r *poolReconciler) ownershipOf(ctx context.Context, obj client.Object) openapi.Ownership {
+	if svc, ok := obj.(*corev1.Service); ok {
+		return poolOwnership(obj, svc)
+	}
+
+	if binding, ok := obj.(*v1alpha1.BfeServiceBinding); ok {
+		svc := &corev1.Service{}
+		key := client.ObjectKey{Namespace: binding.Namespace, Name: binding.Spec.ServiceName}
+		if err := r.Get(ctx, key, svc); err == nil {
+			return poolOwnership(obj, svc)
+		}
+	}
+	// the service is gone, pools are told apart without uid
+	return poolOwnership(obj, nil)
+}
+
+// claim checks none of pools is owned by another service, then records owner of them
+func (o *poolOwners) claim(ctx context.Context, reader client.Reader, owner string, pools openapi.ProductPoolnameList) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if !o.seeded {
+		if err := o.seed(ctx, reader); err != nil {
+			return err
+		}
+		o.seeded = true
+	}
+
+	for _, p := range pools {
+		if cur, ok := o.owners[p]; ok && cur != owner {
+			metrics.PoolCollisions.WithLabelValues(p.Product).Inc()
+			return &PoolCollisionError{Pool: p, Owner: cur}
+		}
+	}
+	for _, p := range pools {
+		o.owners[p] = owner
+	}
+
+	return nil
+}
+
+// set replaces the pools owned by owner
+func (o *poolOwners) set(owner string, pools openapi.ProductPoolnameList) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for p, cur := range o.owners {
+		if cur == owner {
+			delete(o.owners, p)
+		}
+	}
+	for _, p := range pools {
+		o.owners[p] = owner
+	}
+}
+
+// seed loads the owners from the pools recorded in the annotation of services and bindings
+func (o *poolOwners) seed(ctx context.Context, reader client.Reader) error {
+	svcs := &corev1.ServiceList{}
+	if err := reader.List(ctx, svcs); err != nil {
+		return fmt.Errorf("fail to list services for pool owners: %s", err)
+	}
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
+		o.seedObject(svc)
+	}
+
+	if option.Opts.EnableServiceBinding {
+		bindings := &v1alpha1.BfeServiceBindingList{}
+		if err := reader.List(ctx, bindings); err != nil {
+			return fmt.Errorf("fail to list service bindings for pool owners: %s", err)
+		}
+		for i := range bindings.Items {
+			binding := &bindings.Items[i]
+			o.seedObject(binding)
+		}
+	}
+
+	util.HdlLogger.Info("pool owners loaded", "pools", len(o.owners))
+	return nil
+}
+
+func (o *poolOwners) seedObject(obj client.Object) {
+	owner := poolOwnerOf(obj)
+	annotation := obj.GetAnnotations()[ProductPoolResultAnnotationKey]
+	if annotation == "" {
+		return
+	}
+	poollist, err := extractPoolList(annotation)
+	if err != nil {
+		return
+	}
+	for _, p := range poollist {
+		if cur, ok := o.owners[p]; ok && cur != owner {
+			util.HdlLogger.Info("pool recorded by multiple services", "product", p.Product, "pool", p.Poolname,
+				"owner", cur, "other", owner)
+			continue
+		}
+		o.owners[p] = owner
+	}
+}
+
+func genPoolNameListByBindings(product string, bindings []openapi.PoolBinding) openapi.ProductPoolnameList {
+	pools := make(openapi.ProductPoolnameList, 0, len(bindings))
+	for _, b := range bindings {
+		pools = append(pools, openapi.ProductPoolname{Product: product, Poolname: b.PoolName})
+	}
+	return pools
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// needResync checks whether a resync requested by annotation has not been processed yet
+func needResync(obj client.Object) bool {
+	annotations := obj.GetAnnotations()
+	resyncAt := annotations[ResyncAtAnnotationKey]
+	return resyncAt != "" && resyncAt != annotations[ResyncProcessedAnnotationKey]
+}
+
+// markResynced echoes the processed resync-at value, so that tooling knows the resync took effect
+func (r *poolReconciler) markResynced(ctx context.Context, obj client.Object) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	resyncAt := annotations[ResyncAtAnnotationKey]
+	annotations[ResyncProcessedAnnotationKey] = resyncAt
+	obj.SetAnnotations(annotations)
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		util.K8sCLogger.Info("failed to mark resynced", "namespace", obj.GetNamespace(), "name", obj.GetName(), "resyncAt", resyncAt)
+		return err
+	}
+
+	util.HdlLogger.Info("resync processed", "namespace", obj.GetNamespace(), "name", obj.GetName(), "resyncAt", resyncAt)
+	return nil
+}
@@ -17,21 +17,26 @@ package loadbalancer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	openapi "github.com/bfenetworks/service-controller/internal/alb"
 	"github.com/bfenetworks/service-controller/internal/controllers/filter"
+	"github.com/bfenetworks/service-controller/internal/controllers/readiness"
+	"github.com/bfenetworks/service-controller/internal/metrics"
 	"github.com/bfenetworks/service-controller/internal/option"
 	util "github.com/bfenetworks/service-controller/internal/util"
 )
@@ -40,13 +45,24 @@ const (
 	FinalizerName                  = "k8s.bfenetworks.com/delete-protection"
 	BfenetworksAnnotationPrefix    = filter.BfenetworksAnnotationPrefix
 	ProductPoolResultAnnotationKey = BfenetworksAnnotationPrefix + "productpool-result"
+	DeletionPolicyAnnotationKey    = BfenetworksAnnotationPrefix + "deletion-policy"
+	PausedAnnotationKey            = BfenetworksAnnotationPrefix + "paused"
+	ResyncAtAnnotationKey          = BfenetworksAnnotationPrefix + "resync-at"
+	ResyncProcessedAnnotationKey   = BfenetworksAnnotationPrefix + "resync-at-processed"
+	EndpointsDebounceAnnotationKey = BfenetworksAnnotationPrefix + "endpoints-debounce"
+	EndpointsMaxDelayAnnotationKey = BfenetworksAnnotationPrefix + "endpoints-max-delay"
+	ApproveShrinkAnnotationKey     = BfenetworksAnnotationPrefix + "approve-shrink"
+	AdoptPoolAnnotationKey         = BfenetworksAnnotationPrefix + "adopt-pool"
+	AdoptedPoolsAnnotationKey      = BfenetworksAnnotationPrefix + "adopted-pools"
+	SharedPoolAnnotationKey        = BfenetworksAnnotationPrefix + "shared-pool"
+	ClusterWeightAnnotationKey     = BfenetworksAnnotationPrefix + "cluster-weight"
 
 	OPTypeDelete = "delete"
 	OPTypeUpdate = "update"
 )
 
-func AddServiceController(mgr manager.Manager) error {
-	reconciler := newServiceReconciler(mgr)
+func AddServiceController(mgr manager.Manager, provider *openapi.AlbProvider) error {
+	reconciler := newServiceReconciler(mgr, provider)
 	if err := reconciler.setupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to create service controller for loadbalancer: %s", err)
 	}
@@ -54,8 +70,8 @@ func AddServiceController(mgr manager.Manager) error {
 	return nil
 }
 
-// ServiceReconciler reconciles a Service object
-type ServiceReconciler struct {
+// poolReconciler holds what is shared by the reconcilers writing BFE pools
+type poolReconciler struct {
 	ExternalLB *openapi.AlbProvider
 
 	client.Client
@@ -63,15 +79,26 @@ type ServiceReconciler struct {
 	recorder record.EventRecorder
 }
 
-func newServiceReconciler(mgr manager.Manager) *ServiceReconciler {
-	return &ServiceReconciler{
-		ExternalLB: openapi.NewAlbProvider(option.Opts.ExternalLB),
+func newPoolReconciler(mgr manager.Manager, provider *openapi.AlbProvider) poolReconciler {
+	return poolReconciler{
+		ExternalLB: provider,
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
 		recorder:   mgr.GetEventRecorderFor("service-controller"),
 	}
 }
 
+// ServiceReconciler reconciles a Service object
+type ServiceReconciler struct {
+	poolReconciler
+}
+
+func newServiceReconciler(mgr manager.Manager, provider *openapi.AlbProvider) *ServiceReconciler {
+	return &ServiceReconciler{
+		poolReconciler: newPoolReconciler(mgr, provider),
+	}
+}
+
 func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	svc := &corev1.Service{}
 	err := r.Get(ctx, client.ObjectKey{
@@ -90,34 +117,76 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if isdel && err != nil && option.Opts.SkipNilSvcDelete {
 		//since we use finalizer, in this case(svc is empty), we can skip it
 		util.K8sCLogger.Info("reconciling service, skip nil delete", "namespace", req.Namespace, "name", req.Name, "isdel", isdel)
+		if apierrors.IsNotFound(err) {
+			// gone before its first reconcile, nothing to sync
+			readiness.MarkSynced(req.NamespacedName.String())
+		}
 		return ctrl.Result{}, nil
 	}
 
+	if !isdel && option.Opts.EnableServiceBinding {
+		if bindings := serviceBindings(ctx, r.Client, svc); len(bindings) > 0 {
+			// the service is published by BfeServiceBinding, ignore the label
+			util.K8sCLogger.Info("reconciling service, skip service with binding", "namespace", req.Namespace, "name", req.Name)
+			if isPaused(svc) {
+				// no BFE operation while paused, the pools are handed over once resumed
+				readiness.MarkSynced(req.NamespacedName.String())
+				return ctrl.Result{}, nil
+			}
+			done, err := r.handOverPools(ctx, svc, bindings)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !done {
+				// wait for the bindings to write their pools, which may be the ones of the label
+				return ctrl.Result{RequeueAfter: handOverInterval}, nil
+			}
+			readiness.MarkSynced(req.NamespacedName.String())
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if isPaused(svc) {
+		return r.reconcilePaused(ctx, req, svc, isdel)
+	}
+
 	op := OPTypeDelete
+	var pools []openapi.PoolStatus
 	if !isdel {
 		//newly create service, add finalizer firstly
 		if !hasFinalizer(svc, FinalizerName) {
 			err = r.addFinalizer(ctx, svc)
-			if err == nil {
-				util.K8sCLogger.Info("reconciling service succ to add finalizer", "namespace", req.Namespace, "name", req.Name, "isdel", isdel)
-				//will handle it in next msg
-				return ctrl.Result{}, err
-			} else {
+			if err != nil {
 				util.K8sCLogger.Info("reconciling service failed to add finalizer", "namespace", req.Namespace, "name", req.Name, "isdel", isdel)
 				return ctrl.Result{Requeue: true, RequeueAfter: 30 * time.Second}, err
 			}
+			// the patch of finalizer is filtered out by IgnoreSelfUpdate, go on handling it
+			util.K8sCLogger.Info("reconciling service succ to add finalizer", "namespace", req.Namespace, "name", req.Name, "isdel", isdel)
 		}
 		op = OPTypeUpdate
-		err = r.ensurePool(ctx, req.Namespace, req.Name, svc)
+		pools, err = r.ensurePool(ctx, req.Namespace, req.Name, svc)
+		if err == nil && needResync(svc) {
+			r.markResynced(ctx, svc)
+		}
+		if err == nil {
+			r.consumeShrinkApproval(ctx, svc, pools)
+		}
 	} else {
-		err = r.deletePool(ctx, svc)
+		err = r.deletePool(ctx, svc, deletionPolicy(svc))
 		if err == nil || option.Opts.ForceRmFinalizer {
 			r.removeFinalizer(ctx, svc)
 		}
 	}
 
 	r.emitEvent(svc, err, req.Namespace, req.Name, op)
-	r.handleResultConfigmap(ctx, req.Namespace, req.Name, err, op)
+	r.handleResult(ctx, svc, req.Namespace, req.Name, pools, err, op)
+
+	if err == nil {
+		readiness.MarkSynced(req.NamespacedName.String())
+		if !isdel {
+			return sharedPoolResync(svc), nil
+		}
+	}
 
 	if err != nil {
 		if option.Opts.RetryIntervalUnitForErrS > 0 {
@@ -129,72 +198,245 @@ func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return ctrl.Result{}, err
 }
 
-func (r *ServiceReconciler) ensurePool(ctx context.Context, namespace string, name string, service *corev1.Service) error {
+func (r *ServiceReconciler) ensurePool(ctx context.Context, namespace string, name string, service *corev1.Service) ([]openapi.PoolStatus, error) {
 	ep := &corev1.Endpoints{}
 	err := r.Get(ctx, client.ObjectKey{
 		Namespace: namespace,
 		Name:      name,
 	}, ep)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	labels := service.GetObjectMeta().GetLabels()
 
+	var pools []openapi.PoolStatus
 	if product, ok := labels["bfe-product"]; ok {
-		err = r.ensureProductPool(ctx, service, ep, product)
+		pools, err = r.ensureProductPool(ctx, service, ep, product)
 	}
 
-	return err
+	return pools, err
 }
 
-func (r *ServiceReconciler) ensureProductPool(ctx context.Context, service *corev1.Service, ep *corev1.Endpoints, product string) error {
-	var poolNames []string
+func (r *ServiceReconciler) ensureProductPool(ctx context.Context, service *corev1.Service, ep *corev1.Endpoints, product string) ([]openapi.PoolStatus, error) {
+	var pools []openapi.PoolStatus
 	var err1 error
 
-	poolNames, err1 = r.ExternalLB.EnsureProductPool(ctx, product, service, ep, option.Opts.ClusterName)
-	newpools := genProductPoolNameList(product, poolNames)
+	bindings, err := servicePoolBindings(service, product)
+	if err != nil {
+		return nil, err
+	}
+	owner := ownerKey(service.Namespace, service.Name)
+	if err := owners.claim(ctx, r.Client, owner, genPoolNameListByBindings(product, bindings)); err != nil {
+		return nil, err
+	}
+
+	opts := ensureOptions(service, service)
+	pools, err1 = r.ExternalLB.EnsureProductPoolByBindings(ctx, product, ep, bindings, opts)
+	newpools := genProductPoolNameList(product, pools)
+	r.recordPools(ctx, service, newpools, genAdoptedPoolNameList(product, bindings), err1)
 
-	annotation := service.Annotations[ProductPoolResultAnnotationKey]
+	return pools, err1
+}
+
+// ensureOptions returns the options of ensuring the pools of obj, which publishes svc
+func ensureOptions(obj client.Object, svc *corev1.Service) openapi.EnsureOptions {
+	return openapi.EnsureOptions{
+		Force:          needResync(obj),
+		MaxShrinkRatio: option.Opts.MaxShrinkRatio,
+		MinInstances:   option.Opts.MinPoolInstances,
+		ApproveShrink:  strings.EqualFold(obj.GetAnnotations()[ApproveShrinkAnnotationKey], "true"),
+		Ownership:      poolOwnership(obj, svc),
+	}
+}
+
+// consumeShrinkApproval removes the approve-shrink annotation of obj once it let a held shrink
+// through, so that the approval is used only once and the guard is back for later changes
+func (r *poolReconciler) consumeShrinkApproval(ctx context.Context, obj client.Object, pools []openapi.PoolStatus) error {
+	approved := false
+	for _, p := range pools {
+		if p.ShrinkApproved {
+			approved = true
+			break
+		}
+	}
+	if !approved {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	delete(annotations, ApproveShrinkAnnotationKey)
+	obj.SetAnnotations(annotations)
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		util.K8sCLogger.Info("failed to remove shrink approval", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return err
+	}
+
+	util.HdlLogger.Info("shrink approval consumed", "namespace", obj.GetNamespace(), "name", obj.GetName())
+	return nil
+}
+
+// recordPools deletes the pools recorded in the annotation of obj but not in newpools
+// (only if ensuring succeeded), then records newpools and the undeleted ones to the annotation
+// and the pool owners. The adopted pools no longer in newpools are released instead of deleted.
+func (r *poolReconciler) recordPools(ctx context.Context, obj client.Object, newpools, adopted openapi.ProductPoolnameList, err1 error) {
+	annotation := obj.GetAnnotations()[ProductPoolResultAnnotationKey]
+	oldAdopted := adoptedPoolList(obj)
 
 	if annotation != "" {
 		oldpools, err := extractPoolList(annotation)
 		if err == nil {
 			diff := diffList(oldpools, newpools)
 			if err1 == nil {
-				delnames, err := r.ExternalLB.DeleteProductPoolByList(ctx, diff)
+				released := intersectList(diff, oldAdopted)
+				diff = diffList(diff, released)
+				unreleased, err := r.releasePools(ctx, obj, released)
+				if err != nil {
+					util.HdlLogger.Error(err, "release adopted product pools")
+				}
+
+				delnames, err := r.ExternalLB.DeleteProductPoolByList(ctx, diff, r.ownershipOf(ctx, obj))
 				if err != nil {
 					util.HdlLogger.Error(err, "del diff product pools")
 				}
-				diff = diffList(diff, delnames)
+				diff = append(diffList(diff, delnames), unreleased...)
 			}
 			newpools = append(newpools, diff...)
 		}
 	}
-	r.addAnnotationByList(ctx, service, newpools, ProductPoolResultAnnotationKey)
+	r.addAnnotationByList(ctx, obj, newpools, ProductPoolResultAnnotationKey)
+	// pools adopted earlier stay adopted as long as they are recorded
+	adopted = append(intersectList(adopted, newpools), diffList(intersectList(oldAdopted, newpools), adopted)...)
+	r.recordAdoptedPools(ctx, obj, adopted)
+	owners.set(poolOwnerOf(obj), newpools)
+}
 
-	return err1
+// releasePools releases the adopted pools of obj, and returns the ones failed to release
+func (r *poolReconciler) releasePools(ctx context.Context, obj client.Object, pools openapi.ProductPoolnameList) (openapi.ProductPoolnameList, error) {
+	if len(pools) == 0 {
+		return nil, nil
+	}
+	relnames, err := r.ExternalLB.ReleaseProductPoolByList(ctx, pools, r.ownershipOf(ctx, obj))
+	util.HdlLogger.Info("release adopted product pools", "namespace", obj.GetNamespace(), "name", obj.GetName(), "pools", relnames)
+	return diffList(pools, relnames), err
 }
 
-func (r *ServiceReconciler) deletePool(ctx context.Context, service *corev1.Service) error {
-	if service == nil {
+// recordAdoptedPools records the adopted pools of obj to the annotation, which is removed if there is none
+func (r *poolReconciler) recordAdoptedPools(ctx context.Context, obj client.Object, adopted openapi.ProductPoolnameList) error {
+	if len(adopted) > 0 {
+		return r.addAnnotationByList(ctx, obj, adopted, AdoptedPoolsAnnotationKey)
+	}
+	if _, ok := obj.GetAnnotations()[AdoptedPoolsAnnotationKey]; !ok {
 		return nil
 	}
 
-	annotation := service.Annotations[ProductPoolResultAnnotationKey]
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	delete(annotations, AdoptedPoolsAnnotationKey)
+	obj.SetAnnotations(annotations)
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		util.K8sCLogger.Info("failed to remove annotation", "key", AdoptedPoolsAnnotationKey)
+		return err
+	}
+	return nil
+}
+
+func (r *poolReconciler) deletePool(ctx context.Context, obj client.Object, policy string) error {
+	if obj == nil {
+		return nil
+	}
+	if !option.IsValidDeletionPolicy(policy) {
+		return fmt.Errorf("invalid deletion policy %q, should be %s, %s or %s", policy,
+			option.DeletionPolicyDelete, option.DeletionPolicyRetain, option.DeletionPolicyClear)
+	}
+
+	annotation := obj.GetAnnotations()[ProductPoolResultAnnotationKey]
 	if annotation != "" {
 		if poollist, err := extractPoolList(annotation); err == nil {
-			// delete the product pools
-			_, err = r.ExternalLB.DeleteProductPoolByList(ctx, poollist)
+			// adopted pools are released instead of deleted or cleared
+			released := intersectList(poollist, adoptedPoolList(obj))
+			poollist = diffList(poollist, released)
+			if policy != option.DeletionPolicyRetain {
+				if _, err := r.releasePools(ctx, obj, released); err != nil {
+					return err
+				}
+			}
+			switch policy {
+			case option.DeletionPolicyRetain:
+				util.HdlLogger.Info("retain product pools", "namespace", obj.GetNamespace(), "name", obj.GetName(), "pools", annotation)
+			case option.DeletionPolicyClear:
+				// keep the product pools but remove the instances
+				_, err = r.ExternalLB.ClearProductPoolByList(ctx, poollist, r.ownershipOf(ctx, obj))
+			default:
+				// delete the product pools
+				_, err = r.ExternalLB.DeleteProductPoolByList(ctx, poollist, r.ownershipOf(ctx, obj))
+			}
 			if err != nil {
 				return err
 			}
 		}
 	}
+	owners.set(poolOwnerOf(obj), nil)
 
 	return nil
 }
 
+// deletionPolicy returns the deletion policy in annotation of obj, or the default one
+func deletionPolicy(obj client.Object) string {
+	if policy, ok := obj.GetAnnotations()[DeletionPolicyAnnotationKey]; ok {
+		return strings.ToLower(strings.TrimSpace(policy))
+	}
+	return option.Opts.DeletionPolicy
+}
+
+// isSharedPool returns whether the pools of obj are shared with other clusters, by annotation or the default
+func isSharedPool(obj client.Object) bool {
+	if value, ok := obj.GetAnnotations()[SharedPoolAnnotationKey]; ok {
+		return strings.EqualFold(strings.TrimSpace(value), "true")
+	}
+	return option.Opts.SharedPool
+}
+
+// sharedPoolResync returns the result requeueing obj to re-check its shared pools. BFE pools have no
+// optimistic locking, the last writer wins, so the instances of this cluster overwritten by a peer
+// after the update was verified are only restored by checking again.
+func sharedPoolResync(obj client.Object) ctrl.Result {
+	if !isSharedPool(obj) || option.Opts.SharedPoolResyncS <= 0 {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: time.Duration(option.Opts.SharedPoolResyncS) * time.Second}
+}
+
+// poolClusterName returns the cluster name in the pool names of obj, which is empty for shared pools
+func poolClusterName(obj client.Object) string {
+	if isSharedPool(obj) {
+		return ""
+	}
+	return option.Opts.ClusterName
+}
+
+// ListTargetServices returns the keys(namespace/name) of the services handled by this controller
+func ListTargetServices(ctx context.Context, c client.Reader) ([]string, error) {
+	svcs := &corev1.ServiceList{}
+	if err := c.List(ctx, svcs); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(svcs.Items))
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
+		if !filter.IsWatchedNamespace(svc.Namespace) || !filter.HasProductLabel(svc) {
+			continue
+		}
+		keys = append(keys, client.ObjectKeyFromObject(svc).String())
+	}
+
+	return keys, nil
+}
+
 func needDelete(svc *corev1.Service) bool {
 	if svc != nil && svc.DeletionTimestamp != nil && !svc.DeletionTimestamp.IsZero() {
 		return true
@@ -202,11 +444,11 @@ func needDelete(svc *corev1.Service) bool {
 	return false
 }
 
-func (r *ServiceReconciler) addFinalizer(ctx context.Context, service *corev1.Service) error {
-	patch := client.MergeFrom(service.DeepCopy())
-	controllerutil.AddFinalizer(service, FinalizerName)
+func (r *poolReconciler) addFinalizer(ctx context.Context, obj client.Object) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	controllerutil.AddFinalizer(obj, FinalizerName)
 
-	if err := r.Patch(ctx, service, patch); err != nil {
+	if err := r.Patch(ctx, obj, patch); err != nil {
 		util.K8sCLogger.Info("failed to add finalizer", "FinalizerName", FinalizerName)
 		return err
 	}
@@ -243,27 +485,38 @@ func diffList(oldlist, newlist openapi.ProductPoolnameList) openapi.ProductPooln
 	return diff
 }
 
-func genProductPoolNameList(product string, poolNames []string) openapi.ProductPoolnameList {
-	newlist := make(openapi.ProductPoolnameList, 0, len(poolNames))
-	for _, n := range poolNames {
+// intersectList returns the pools of list also in other
+func intersectList(list, other openapi.ProductPoolnameList) openapi.ProductPoolnameList {
+	return diffList(list, diffList(list, other))
+}
+
+func genProductPoolNameList(product string, pools []openapi.PoolStatus) openapi.ProductPoolnameList {
+	newlist := make(openapi.ProductPoolnameList, 0, len(pools))
+	for _, p := range pools {
 		newlist = append(newlist, openapi.ProductPoolname{
 			Product:  product,
-			Poolname: n,
+			Poolname: p.Name,
 		})
 	}
 	return newlist
 }
 
-func (r *ServiceReconciler) addAnnotationByList(ctx context.Context, service *corev1.Service, pools openapi.ProductPoolnameList, annotationKey string) error {
-	patch := client.MergeFrom(service.DeepCopy())
-	if service.Annotations == nil {
-		service.Annotations = make(map[string]string)
+func (r *poolReconciler) addAnnotationByList(ctx context.Context, obj client.Object, pools openapi.ProductPoolnameList, annotationKey string) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
 	}
 
 	jsonstr, _ := json.Marshal(pools)
-	service.Annotations[annotationKey] = string(jsonstr)
+	if value, ok := annotations[annotationKey]; ok && value == string(jsonstr) {
+		metrics.PatchesSkipped.WithLabelValues(annotationKey).Inc()
+		return nil
+	}
+	annotations[annotationKey] = string(jsonstr)
+	obj.SetAnnotations(annotations)
 
-	if err := r.Patch(ctx, service, patch); err != nil {
+	if err := r.Patch(ctx, obj, patch); err != nil {
 		util.K8sCLogger.Info("failed to add annotation", "key", annotationKey, "pool", string(jsonstr))
 		return err
 	}
@@ -271,12 +524,12 @@ func (r *ServiceReconciler) addAnnotationByList(ctx context.Context, service *co
 	return nil
 }
 
-func (r *ServiceReconciler) removeFinalizer(ctx context.Context, service *corev1.Service) error {
+func (r *poolReconciler) removeFinalizer(ctx context.Context, obj client.Object) error {
 	// remove our finalizer from the list and update it.
-	if hasFinalizer(service, FinalizerName) {
-		patch := client.MergeFrom(service.DeepCopy())
-		controllerutil.RemoveFinalizer(service, FinalizerName)
-		if err := r.Patch(ctx, service, patch); err != nil {
+	if hasFinalizer(obj, FinalizerName) {
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		controllerutil.RemoveFinalizer(obj, FinalizerName)
+		if err := r.Patch(ctx, obj, patch); err != nil {
 			util.K8sCLogger.Info("failed to remove finalizer", "FinalizerName", FinalizerName)
 			return err
 		}
@@ -284,8 +537,8 @@ func (r *ServiceReconciler) removeFinalizer(ctx context.Context, service *corev1
 	return nil
 }
 
-func hasFinalizer(service *corev1.Service, finalizer string) bool {
-	for _, item := range service.GetFinalizers() {
+func hasFinalizer(obj client.Object, finalizer string) bool {
+	for _, item := range obj.GetFinalizers() {
 		if item == finalizer {
 			return true
 		}
@@ -293,7 +546,17 @@ func hasFinalizer(service *corev1.Service, finalizer string) bool {
 	return false
 }
 
-func (r *ServiceReconciler) handleResultConfigmap(ctx context.Context, ns string, name string, err error, op string) error {
+func (r *ServiceReconciler) handleResult(ctx context.Context, svc *corev1.Service, ns string, name string,
+	pools []openapi.PoolStatus, err error, op string) {
+	if option.Opts.ResultMode != option.ResultModeConfigmap {
+		r.handleResultCondition(ctx, svc, pools, err, op)
+	}
+	if option.Opts.ResultMode != option.ResultModeCondition {
+		r.handleResultConfigmap(ctx, ns, name, err, op, svc.Annotations[ResyncProcessedAnnotationKey])
+	}
+}
+
+func (r *ServiceReconciler) handleResultConfigmap(ctx context.Context, ns string, name string, err error, op string, resyncAt string) error {
 	dstname := name + ".result"
 	dst := &corev1.ConfigMap{}
 	dst.ObjectMeta.Name = dstname
@@ -326,6 +589,9 @@ func (r *ServiceReconciler) handleResultConfigmap(ctx context.Context, ns string
 		}
 		ts := time.Now()
 		dst.Data["timestamp"] = ts.Format("2006-01-02 15:04:05.000")
+		if resyncAt != "" {
+			dst.Data["resync-at"] = resyncAt
+		}
 
 		if terr != nil {
 			terr = r.Create(ctx, dst)
@@ -341,10 +607,17 @@ func (r *ServiceReconciler) handleResultConfigmap(ctx context.Context, ns string
 	return nil
 }
 
-func (r *ServiceReconciler) emitEvent(object runtime.Object, err error, namespace, name string, extra string) {
+func (r *poolReconciler) emitEvent(object runtime.Object, err error, namespace, name string, extra string) {
 	status := "OK"
 	objName := namespace + "::" + name
-	if err == nil {
+	var perr *pausedError
+	if errors.As(err, &perr) {
+		status = err.Error()
+		r.recorder.Event(object, corev1.EventTypeNormal, extra+" paused For "+objName, status)
+	} else if openapi.IsCircuitOpen(err) {
+		// reported once by the controller when the circuit breaker opens
+		status = err.Error()
+	} else if err == nil {
 		r.recorder.Event(object, corev1.EventTypeNormal, extra+" success For "+objName, status)
 	} else {
 		status = err.Error()
@@ -353,13 +626,20 @@ func (r *ServiceReconciler) emitEvent(object runtime.Object, err error, namespac
 	util.HdlLogger.Info("servie controller status", "object", objName, "op", extra, "msg", status)
 }
 
+// ignoreSelfUpdate filters out the updates made by the controller itself, including removing
+// the approval of shrink once used
+func ignoreSelfUpdate() predicate.Funcs {
+	return filter.IgnoreSelfUpdateWithRemovals(FinalizerName, []string{ApproveShrinkAnnotationKey},
+		ProductPoolResultAnnotationKey, AdoptedPoolsAnnotationKey, ResyncProcessedAnnotationKey)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceReconciler) setupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Service{}, builder.WithPredicates(filter.NamespaceFilter(), filter.LabelFilter())).
+		For(&corev1.Service{}, builder.WithPredicates(filter.NamespaceFilter(), filter.LabelFilter(), ignoreSelfUpdate())).
 		Watches(
 			&corev1.Endpoints{},
-			&handler.EnqueueRequestForObject{},
+			newDebounceHandler(mgr.GetClient(), enqueueEndpoints),
 			builder.WithPredicates(filter.NamespaceFilter(), filter.LabelFilter()),
 		).
 		Complete(r)
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/bfenetworks/service-controller/internal/controllers/readiness"
+	"github.com/bfenetworks/service-controller/internal/option"
+)
+
+func TestReconcileGoneServiceSynced(t *testing.T) {
+	bfe := newFakeBFE(t, nil)
+	r := &ServiceReconciler{poolReconciler: newTestReconciler(t, bfe)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	synced := make(chan bool, 1)
+	go func() {
+		synced <- readiness.WaitInitialSync(ctx, []string{"default/gone"})
+	}()
+
+	// listed as a sync target, but deleted before its first reconcile
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "gone"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %s", err)
+	}
+	if !<-synced {
+		t.Errorf("initial sync should not wait for a deleted service")
+	}
+	if n := bfe.count("DELETE"); n != 0 {
+		t.Errorf("%d DELETE sent for a deleted service without finalizer", n)
+	}
+}
+
+func TestReconcileSharedPoolResync(t *testing.T) {
+	for _, shared := range []bool{true, false} {
+		bfe := newFakeBFE(t, emptyPoolBFE)
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "web",
+				UID:         "uid-web",
+				Labels:      map[string]string{"bfe-product": "demo"},
+				Annotations: map[string]string{SharedPoolAnnotationKey: strconv.FormatBool(shared)},
+				Finalizers:  []string{FinalizerName},
+			},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+		}
+		ep := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+		r := &ServiceReconciler{poolReconciler: newTestReconciler(t, bfe, svc, ep)}
+		option.Opts.ClusterName = "cluster-a"
+		option.Opts.AdoptUnownedPools = true
+
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}}
+		result, err := r.Reconcile(context.Background(), req)
+		if err != nil {
+			t.Fatalf("shared %v: Reconcile: %s", shared, err)
+		}
+
+		// a shared pool may be overwritten by peers at any time, so it is checked again
+		want := time.Duration(0)
+		if shared {
+			want = time.Duration(option.SharedPoolResyncS) * time.Second
+		}
+		if result.RequeueAfter != want {
+			t.Errorf("shared %v: requeue after %s, want %s", shared, result.RequeueAfter, want)
+		}
+	}
+}
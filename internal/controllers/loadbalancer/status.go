@@ -0,0 +1,155 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+const (
+	// ConditionBfeSynced reports whether the last reconcile of the service succeeded
+	ConditionBfeSynced = "BfeSynced"
+	// ConditionBfePoolsReady reports whether every pool of the service has instances in BFE
+	ConditionBfePoolsReady = "BfePoolsReady"
+
+	ReasonSynced        = "Synced"
+	ReasonSyncFailed    = "SyncFailed"
+	ReasonDeleteFailed  = "DeleteFailed"
+	ReasonPoolsReady    = "PoolsReady"
+	ReasonEmptyPools    = "EmptyPools"
+	ReasonNoPools       = "NoPools"
+	ReasonPaused        = "Paused"
+	ReasonShrinkHeld    = "ShrinkHeld"
+	ReasonPoolCollision = "PoolCollision"
+	ReasonNotOwned      = "NotOwned"
+	ReasonNoService     = "ServiceNotFound"
+)
+
+// handleResultCondition records the result of reconcile in status.conditions of the service
+func (r *ServiceReconciler) handleResultCondition(ctx context.Context, svc *corev1.Service,
+	pools []openapi.PoolStatus, err error, op string) error {
+	if svc == nil || svc.UID == "" {
+		// service does not exist any more
+		return nil
+	}
+	if op == OPTypeDelete && err == nil {
+		// service will be gone soon
+		return nil
+	}
+
+	patch := client.MergeFrom(svc.DeepCopy())
+	for _, cond := range genConditions(svc.Generation, pools, err, op) {
+		meta.SetStatusCondition(&svc.Status.Conditions, cond)
+	}
+
+	if terr := r.Status().Patch(ctx, svc, patch); terr != nil {
+		util.HdlLogger.Error(terr, "fail to patch service status", "namespace", svc.Namespace, "name", svc.Name)
+		return terr
+	}
+
+	return nil
+}
+
+func genConditions(generation int64, pools []openapi.PoolStatus, err error, op string) []metav1.Condition {
+	var perr *pausedError
+	if errors.As(err, &perr) {
+		// nothing is written to BFE, pools are left as they are
+		return []metav1.Condition{{
+			Type:               ConditionBfeSynced,
+			Status:             metav1.ConditionUnknown,
+			Reason:             ReasonPaused,
+			Message:            err.Error(),
+			ObservedGeneration: generation,
+		}}
+	}
+
+	synced := metav1.Condition{
+		Type:               ConditionBfeSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonSynced,
+		Message:            fmt.Sprintf("%d pool(s) synced to BFE", len(pools)),
+		ObservedGeneration: generation,
+	}
+	if err != nil {
+		synced.Status = metav1.ConditionFalse
+		var serr *openapi.ShrinkError
+		var cerr *PoolCollisionError
+		var oerr *openapi.NotOwnedError
+		var nerr *ServiceNotFoundError
+		synced.Reason = ReasonSyncFailed
+		if op == OPTypeDelete {
+			synced.Reason = ReasonDeleteFailed
+		} else if errors.As(err, &serr) {
+			synced.Reason = ReasonShrinkHeld
+		} else if errors.As(err, &cerr) {
+			synced.Reason = ReasonPoolCollision
+		} else if errors.As(err, &oerr) && oerr.Collision {
+			synced.Reason = ReasonPoolCollision
+		} else if errors.As(err, &oerr) {
+			synced.Reason = ReasonNotOwned
+		} else if errors.As(err, &nerr) {
+			synced.Reason = ReasonNoService
+		}
+		synced.Message = err.Error()
+	}
+
+	if op == OPTypeDelete {
+		return []metav1.Condition{synced}
+	}
+
+	ready := metav1.Condition{
+		Type:               ConditionBfePoolsReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonPoolsReady,
+		ObservedGeneration: generation,
+	}
+
+	empty := make([]string, 0)
+	for _, p := range pools {
+		if p.Instances == 0 {
+			empty = append(empty, p.Name)
+		}
+	}
+
+	switch {
+	case err != nil:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonSyncFailed
+		ready.Message = "last sync to BFE failed"
+	case len(pools) == 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonNoPools
+		ready.Message = "no named port in service"
+	case len(empty) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = ReasonEmptyPools
+		ready.Message = "pool(s) without instance: " + strings.Join(empty, ",")
+	default:
+		ready.Message = fmt.Sprintf("%d pool(s) have instances", len(pools))
+	}
+
+	return []metav1.Condition{synced, ready}
+}
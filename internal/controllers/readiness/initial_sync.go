@@ -0,0 +1,115 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readiness
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bfenetworks/service-controller/internal/metrics"
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// initialSync tracks which services have completed their first reconcile
+// since the controller started.
+type initialSync struct {
+	lock    sync.Mutex
+	done    bool
+	started bool
+	synced  map[string]bool
+	pending map[string]bool
+	allDone chan struct{}
+}
+
+var (
+	tracker = &initialSync{
+		synced:  make(map[string]bool),
+		pending: make(map[string]bool),
+		allDone: make(chan struct{}),
+	}
+)
+
+// MarkSynced records that the service identified by key (namespace/name)
+// has completed a reconcile.
+func MarkSynced(key string) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if tracker.done {
+		return
+	}
+
+	if !tracker.started {
+		// reconciles may finish before the eligible services are listed
+		tracker.synced[key] = true
+		return
+	}
+
+	if tracker.pending[key] {
+		delete(tracker.pending, key)
+		metrics.InitialSyncPendingServices.Set(float64(len(tracker.pending)))
+		if len(tracker.pending) == 0 {
+			close(tracker.allDone)
+		}
+	}
+}
+
+// WaitInitialSync blocks until every service in keys has completed its first
+// reconcile, or ctx is done. It returns false if ctx ended first.
+func WaitInitialSync(ctx context.Context, keys []string) bool {
+	tracker.lock.Lock()
+	tracker.started = true
+	for _, key := range keys {
+		if !tracker.synced[key] {
+			tracker.pending[key] = true
+		}
+	}
+	tracker.synced = nil
+
+	metrics.InitialSyncServices.Set(float64(len(keys)))
+	metrics.InitialSyncPendingServices.Set(float64(len(tracker.pending)))
+	if len(tracker.pending) == 0 {
+		close(tracker.allDone)
+	}
+	tracker.lock.Unlock()
+
+	util.K8sCLogger.Info("waiting for initial sync", "services", len(keys))
+
+	select {
+	case <-tracker.allDone:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// FinishInitialSync stops the tracking and records the result of the initial sync.
+func FinishInitialSync(start time.Time, timeout bool) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	tracker.done = true
+	pending := len(tracker.pending)
+	tracker.pending = nil
+
+	metrics.InitialSyncDone.Set(1)
+	metrics.InitialSyncDurationSeconds.Set(time.Since(start).Seconds())
+	if timeout {
+		metrics.InitialSyncTimeout.Set(1)
+	}
+
+	util.K8sCLogger.Info("initial sync finished", "timeout", timeout, "pending", pending, "duration", time.Since(start).String())
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readiness
+
+import (
+	"context"
+	"time"
+
+	util "github.com/bfenetworks/service-controller/internal/util"
+)
+
+// ProbeFunc checks a dependency of the controller, returns nil if it is healthy
+type ProbeFunc func(ctx context.Context) error
+
+// RunProbe calls probe every interval and updates the status of event, until ctx is done.
+func RunProbe(ctx context.Context, event Event, interval time.Duration, probe ProbeFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := probe(ctx); err != nil {
+			util.K8sCLogger.Info("readiness probe failed", "event", event.String(), "err", err.Error())
+			SetUnreadyWithReason(event, err.Error())
+		} else {
+			SetReady(event)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
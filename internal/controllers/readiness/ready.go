@@ -17,34 +17,140 @@ package readiness
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 type Event int
 
 const (
 	EventRunning Event = iota
+	EventInitialSynced
+	EventApiServerReachable
+	EventApiCircuitClosed
 )
 
+// eventStatus is the state of one readiness event
+type eventStatus struct {
+	ready      bool
+	reportOnly bool // only shown in status page, not affect readiness
+	message    string
+	updateTime time.Time
+}
+
 var (
-	readyStatus = map[Event]bool{
-		EventRunning: false,
+	readyLock   sync.RWMutex
+	readyStatus = map[Event]*eventStatus{
+		EventRunning:       {},
+		EventInitialSynced: {},
 	}
 )
 
+func (e Event) String() string {
+	switch e {
+	case EventRunning:
+		return "running"
+	case EventInitialSynced:
+		return "initial-synced"
+	case EventApiServerReachable:
+		return "api-server-reachable"
+	case EventApiCircuitClosed:
+		return "api-circuit-closed"
+	}
+	return fmt.Sprintf("event-%d", int(e))
+}
+
+// Register adds an event to be checked, the event is unready until SetReady is called.
+// If reportOnly is true, the event is only shown in status page.
+func Register(event Event, reportOnly bool) {
+	readyLock.Lock()
+	defer readyLock.Unlock()
+
+	readyStatus[event] = &eventStatus{reportOnly: reportOnly}
+}
+
 func Checker(_ *http.Request) error {
-	if !readyStatus[EventRunning] {
-		return fmt.Errorf("Controller is not ready")
+	readyLock.RLock()
+	defer readyLock.RUnlock()
+
+	for _, event := range sortedEvents() {
+		status := readyStatus[event]
+		if !status.ready && !status.reportOnly {
+			return fmt.Errorf("Controller is not ready, %s", event)
+		}
 	}
 
 	return nil
 }
 
-func SetReady(event Event) {
-	if !readyStatus[event] {
-		readyStatus[event] = true
+// StatusHandler shows the detail of every readiness event
+func StatusHandler(w http.ResponseWriter, _ *http.Request) {
+	readyLock.RLock()
+	defer readyLock.RUnlock()
+
+	var b strings.Builder
+	for _, event := range sortedEvents() {
+		status := readyStatus[event]
+
+		mark := "+"
+		result := "ok"
+		if !status.ready {
+			mark = "-"
+			result = "failed"
+		}
+		fmt.Fprintf(&b, "[%s]%s %s", mark, event, result)
+		if status.reportOnly {
+			b.WriteString(" (report only)")
+		}
+		if status.message != "" {
+			fmt.Fprintf(&b, ": %s", status.message)
+		}
+		if !status.updateTime.IsZero() {
+			fmt.Fprintf(&b, " (since %s)", status.updateTime.Format(time.RFC3339))
+		}
+		b.WriteString("\n")
 	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+func SetReady(event Event) {
+	setStatus(event, true, "")
 }
 
 func SetUnready(event Event) {
-	readyStatus[event] = false
+	setStatus(event, false, "")
+}
+
+// SetUnreadyWithReason marks the event unready, the reason is shown in status page
+func SetUnreadyWithReason(event Event, reason string) {
+	setStatus(event, false, reason)
+}
+
+func setStatus(event Event, ready bool, message string) {
+	readyLock.Lock()
+	defer readyLock.Unlock()
+
+	status, ok := readyStatus[event]
+	if !ok {
+		status = &eventStatus{}
+		readyStatus[event] = status
+	}
+	if status.ready != ready || status.updateTime.IsZero() {
+		status.updateTime = time.Now()
+	}
+	status.ready = ready
+	status.message = message
+}
+
+func sortedEvents() []Event {
+	events := make([]Event, 0, len(readyStatus))
+	for event := range readyStatus {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
 }
@@ -15,18 +15,22 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"syscall"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
 	"github.com/bfenetworks/service-controller/internal/controllers/loadbalancer"
 	"github.com/bfenetworks/service-controller/internal/controllers/readiness"
 	"github.com/bfenetworks/service-controller/internal/option"
@@ -42,9 +46,16 @@ func Start(scheme *runtime.Scheme) error {
 		return fmt.Errorf("unable to get client config: %s", err)
 	}
 
+	metricsOpts := metricsserver.Options{
+		BindAddress: option.Opts.MetricsAddr,
+		ExtraHandlers: map[string]http.Handler{
+			option.Opts.StatuszEndpointName: http.HandlerFunc(readiness.StatusHandler),
+		},
+	}
+
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
 		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: option.Opts.MetricsAddr},
+		Metrics:                metricsOpts,
 		HealthProbeBindAddress: option.Opts.HealthProbeAddr,
 		LivenessEndpointName:   option.Opts.LivenessEndpointName,
 		ReadinessEndpointName:  option.Opts.ReadinessEndpointName,
@@ -55,9 +66,36 @@ func Start(scheme *runtime.Scheme) error {
 
 	ctx := ctrl.SetupSignalHandler()
 
-	if err := startExternalLB(mgr); err != nil {
+	if option.Opts.ClusterName == "" {
+		log.Info("k8s-cluster-name is empty, pools of services in different clusters sharing one BFE may collide, " +
+			"and shared pools can not tell the instances of clusters apart")
+	}
+
+	provider, err := openapi.NewAlbProvider(option.Opts.ExternalLB)
+	if err != nil {
+		return err
+	}
+	if err := addApiTokenSecretWatcher(ctx, mgr, provider); err != nil {
+		return err
+	}
+	if err := addApiTokenFileWatcher(mgr, provider); err != nil {
+		return err
+	}
+	if err := startExternalLB(mgr, provider); err != nil {
+		return err
+	}
+
+	if err := addApiServerProbe(mgr, provider); err != nil {
 		return err
 	}
+	addApiCircuitListener(mgr, provider)
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		waitInitialSync(ctx, mgr)
+		return nil
+	})); err != nil {
+		return fmt.Errorf("unable to set up initial sync: %s", err)
+	}
 
 	if err := mgr.AddHealthzCheck(option.Opts.LivenessEndpointName, healthz.Ping); err != nil {
 		return fmt.Errorf("unable to set up health check: %s", err)
@@ -81,13 +119,110 @@ func Start(scheme *runtime.Scheme) error {
 	return nil
 }
 
-func startExternalLB(mgr manager.Manager) error {
-	if err := loadbalancer.AddServiceController(mgr); err != nil {
+func startExternalLB(mgr manager.Manager, provider *openapi.AlbProvider) error {
+	if err := loadbalancer.AddServiceController(mgr, provider); err != nil {
 		return err
 	}
+	if option.Opts.EnableServiceBinding {
+		if err := loadbalancer.AddBindingController(mgr, provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitInitialSync keeps the controller unready until informer caches have synced
+// and every target service has been reconciled once, or unready-duration elapses.
+func waitInitialSync(ctx context.Context, mgr manager.Manager) {
+	start := time.Now()
+	syncCtx, cancel := context.WithTimeout(ctx, time.Duration(option.Opts.UnreadyDuration)*time.Second)
+	defer cancel()
+
+	synced := false
+	if mgr.GetCache().WaitForCacheSync(syncCtx) {
+		keys, err := loadbalancer.ListTargetServices(syncCtx, mgr.GetClient())
+		if err != nil {
+			log.Error(err, "fail to list services for initial sync")
+			<-syncCtx.Done()
+		} else {
+			synced = readiness.WaitInitialSync(syncCtx, keys)
+		}
+	}
+
+	if ctx.Err() != nil {
+		// manager is stopping
+		return
+	}
+
+	readiness.FinishInitialSync(start, !synced)
+	readiness.SetReady(readiness.EventInitialSynced)
+}
+
+// addApiServerProbe periodically checks the ALB api server is reachable and the token is valid
+func addApiServerProbe(mgr manager.Manager, provider *openapi.AlbProvider) error {
+	if option.Opts.ApiProbeInterval <= 0 {
+		return nil
+	}
+
+	readiness.Register(readiness.EventApiServerReachable, option.Opts.ApiProbeMode == option.ApiProbeModeReport)
+	interval := time.Duration(option.Opts.ApiProbeInterval) * time.Second
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		readiness.RunProbe(ctx, readiness.EventApiServerReachable, interval, provider.CheckApiServer)
+		return nil
+	})); err != nil {
+		return fmt.Errorf("unable to set up api server probe: %s", err)
+	}
+
 	return nil
 }
 
+// addApiCircuitListener reflects the circuit breaker of ALB api server in readiness, and reports
+// its state changes by one event of the controller instead of one per service
+func addApiCircuitListener(mgr manager.Manager, provider *openapi.AlbProvider) {
+	if option.Opts.ExternalLB.CircuitThreshold <= 0 {
+		return
+	}
+
+	readiness.Register(readiness.EventApiCircuitClosed, option.Opts.ApiCircuitMode == option.ApiCircuitModeReport)
+	readiness.SetReady(readiness.EventApiCircuitClosed)
+
+	recorder := mgr.GetEventRecorderFor("bfe-service-controller")
+	ref := controllerRef()
+	if ref == nil {
+		log.Info("POD_NAME or POD_NAMESPACE env is not set, events of api circuit breaker are not recorded, set them by the downward API")
+	}
+	provider.SetCircuitListener(func(open bool, reason string) {
+		if open {
+			log.Info("api circuit breaker opened, fail api calls fast", "reason", reason)
+			readiness.SetUnreadyWithReason(readiness.EventApiCircuitClosed, reason)
+			if ref != nil {
+				recorder.Event(ref, corev1.EventTypeWarning, "ApiCircuitOpen", "BFE api server unavailable, "+reason)
+			}
+			return
+		}
+
+		log.Info("api circuit breaker closed", "reason", reason)
+		readiness.SetReady(readiness.EventApiCircuitClosed)
+		if ref != nil {
+			recorder.Event(ref, corev1.EventTypeNormal, "ApiCircuitClosed", reason)
+		}
+	})
+}
+
+// controllerRef returns the pod of the controller given by the downward API, nil if unknown
+func controllerRef() *corev1.ObjectReference {
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       name,
+		Namespace:  namespace,
+	}
+}
+
 func startPProfListener() {
 	if len(option.Opts.PProfAddr) <= 0 {
 		return
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	openapi "github.com/bfenetworks/service-controller/internal/alb"
+	"github.com/bfenetworks/service-controller/internal/option"
+)
+
+const (
+	// tokenSecretInterval is the interval of checking whether the token secret changed
+	tokenSecretInterval = 10 * time.Second
+	// tokenFileInterval is the interval of checking whether the token file changed
+	tokenFileInterval = 10 * time.Second
+	// tokenRejectedInterval is the min interval of reloading the token rejected by api server
+	tokenRejectedInterval = time.Second
+)
+
+// waitTokenCheck waits for the next check of the token, on ticker or the rejection of the token.
+// It returns whether the token is rejected, and false for both on ctx done.
+func waitTokenCheck(ctx context.Context, ticker *time.Ticker, provider *openapi.AlbProvider,
+	lastRejected *time.Time) (rejected bool, ok bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, false
+		case <-ticker.C:
+			return false, true
+		case <-provider.TokenRejected():
+			if time.Since(*lastRejected) < tokenRejectedInterval {
+				// reloaded just now, the token is rejected for other reasons
+				continue
+			}
+			*lastRejected = time.Now()
+			return true, true
+		}
+	}
+}
+
+// addApiTokenFileWatcher keeps the token of ALB api server read from bfe-api-token-file updated
+// when the file changes, or at once when the token is rejected
+func addApiTokenFileWatcher(mgr manager.Manager, provider *openapi.AlbProvider) error {
+	if option.Opts.ExternalLB.TokenFile == "" {
+		return nil
+	}
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(tokenFileInterval)
+		defer ticker.Stop()
+		var lastRejected time.Time
+		for {
+			rejected, ok := waitTokenCheck(ctx, ticker, provider, &lastRejected)
+			if !ok {
+				return nil
+			}
+
+			reloaded, err := provider.ReloadTokenFile(rejected)
+			if err != nil {
+				// keep the current token, the file may be partially written
+				log.Error(err, "fail to reload api token file, keep the current token")
+				continue
+			}
+			if reloaded {
+				log.Info("api token reloaded from file", "file", option.Opts.ExternalLB.TokenFile, "rejected", rejected)
+			}
+		}
+	})); err != nil {
+		return fmt.Errorf("unable to set up api token file watcher: %s", err)
+	}
+
+	return nil
+}
+
+// addApiTokenSecretWatcher loads the token of ALB api server from the secret given by
+// bfe-api-token-secret, and keeps it updated when the secret is rotated, checked at once when the
+// token is rejected.
+// The secret is read directly instead of caching all secrets of the cluster.
+func addApiTokenSecretWatcher(ctx context.Context, mgr manager.Manager, provider *openapi.AlbProvider) error {
+	if option.Opts.ExternalLB.TokenSecret == "" {
+		return nil
+	}
+
+	namespace, name, key, err := option.Opts.ExternalLB.GetTokenSecret()
+	if err != nil {
+		return err
+	}
+	ref := types.NamespacedName{Namespace: namespace, Name: name}
+	reader := mgr.GetAPIReader()
+
+	token, err := readTokenSecret(ctx, reader, ref, key)
+	if err != nil {
+		return err
+	}
+	provider.SetToken(token)
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(tokenSecretInterval)
+		defer ticker.Stop()
+		var lastRejected time.Time
+		for {
+			if _, ok := waitTokenCheck(ctx, ticker, provider, &lastRejected); !ok {
+				return nil
+			}
+
+			cur, err := readTokenSecret(ctx, reader, ref, key)
+			if err != nil {
+				// keep the current token
+				log.Error(err, "fail to reload api token secret, keep the current token")
+				continue
+			}
+			if cur != token {
+				token = cur
+				provider.SetToken(token)
+				log.Info("api token reloaded from secret", "secret", ref.String())
+			}
+		}
+	})); err != nil {
+		return fmt.Errorf("unable to set up api token secret watcher: %s", err)
+	}
+
+	return nil
+}
+
+func readTokenSecret(ctx context.Context, reader client.Reader, ref types.NamespacedName, key string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, ref, secret); err != nil {
+		return "", fmt.Errorf("fail to get api token secret %s: %s", ref, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[key]))
+	if token == "" {
+		return "", fmt.Errorf("key %s of api token secret %s is empty", key, ref)
+	}
+	return token, nil
+}
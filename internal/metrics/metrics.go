@@ -0,0 +1,242 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "bfe_service_controller"
+)
+
+var (
+	// InitialSyncServices is the number of eligible services found when the initial sync started
+	InitialSyncServices = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "initial_sync",
+		Name:      "services",
+		Help:      "Number of eligible services to be reconciled before the controller becomes ready.",
+	})
+
+	// InitialSyncPendingServices is the number of eligible services not reconciled yet
+	InitialSyncPendingServices = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "initial_sync",
+		Name:      "pending_services",
+		Help:      "Number of eligible services whose first reconcile has not completed yet.",
+	})
+
+	// InitialSyncDone is 1 once the initial sync finished (or timed out)
+	InitialSyncDone = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "initial_sync",
+		Name:      "done",
+		Help:      "Whether the initial sync has finished, 1 for finished and 0 for in progress.",
+	})
+
+	// InitialSyncDurationSeconds is the time spent by the initial sync
+	InitialSyncDurationSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "initial_sync",
+		Name:      "duration_seconds",
+		Help:      "Time spent from manager start until the initial sync finished.",
+	})
+
+	// InitialSyncTimeout is 1 if the initial sync ended because unready-duration elapsed
+	InitialSyncTimeout = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "initial_sync",
+		Name:      "timeout",
+		Help:      "Whether the initial sync ended because unready-duration elapsed before all services were reconciled.",
+	})
+)
+
+var (
+	// SelfUpdatesIgnored counts the update events ignored because only the controller's own fields changed
+	SelfUpdatesIgnored = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "self_updates_ignored_total",
+		Help:      "Number of update events not reconciled because only the controller's own annotations, finalizer or status changed.",
+	}, []string{"kind"})
+
+	// PatchesSkipped counts the patches skipped because the value is unchanged
+	PatchesSkipped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "patches_skipped_total",
+		Help:      "Number of patches to kubernetes objects skipped because the value is unchanged.",
+	}, []string{"key"})
+)
+
+var (
+	// EndpointsEvents counts the endpoints create and update events
+	EndpointsEvents = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "endpoints",
+		Name:      "events_total",
+		Help:      "Number of endpoints create and update events received.",
+	})
+
+	// EndpointsEventsCoalesced counts the endpoints events merged into a pending reconcile
+	EndpointsEventsCoalesced = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "endpoints",
+		Name:      "events_coalesced_total",
+		Help:      "Number of endpoints events merged into a pending reconcile by debouncing.",
+	})
+
+	// EndpointsPropagationDelaySeconds is the delay added by debouncing
+	EndpointsPropagationDelaySeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "endpoints",
+		Name:      "propagation_delay_seconds",
+		Help:      "Delay from the first endpoints change to the reconcile being enqueued by debouncing.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+)
+
+var (
+	// PoolShrinkHeld counts the pool updates held by the mass instance removal guard
+	PoolShrinkHeld = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pool_shrink_held_total",
+		Help:      "Number of pool updates held because too many instances would be removed.",
+	}, []string{"product"})
+
+	// PoolCollisions counts the pool writes refused because the pool is used by another service
+	PoolCollisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pool_collisions_total",
+		Help:      "Number of pool writes refused because the pool is already used by another service.",
+	}, []string{"product"})
+
+	// PoolNotOwned counts the pool operations refused because the pool is owned by others
+	PoolNotOwned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pool_not_owned_total",
+		Help:      "Number of pool updates, deletions and clears refused because the pool is not owned by the service.",
+	}, []string{"product", "op"})
+
+	// SharedPoolConflicts counts the updates of shared pools overwritten by controllers of other clusters
+	SharedPoolConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shared_pool_conflicts_total",
+		Help:      "Number of shared pool updates found overwritten by concurrent updates from other clusters.",
+	}, []string{"product"})
+)
+
+var (
+	// ApiServerRequests counts the requests to every api server endpoint by result
+	ApiServerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "requests_total",
+		Help:      "Number of requests to the BFE api server by endpoint and result, which is ok, error or connection_error.",
+	}, []string{"endpoint", "result"})
+
+	// ApiServerFailovers counts the requests moved to another endpoint after failing to connect
+	ApiServerFailovers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "failovers_total",
+		Help:      "Number of requests moved to another BFE api server endpoint after failing to connect to this one.",
+	}, []string{"endpoint"})
+
+	// ApiServerRetries counts the retries of requests failed transiently
+	ApiServerRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "retries_total",
+		Help:      "Number of retries of BFE api requests failed by network error, 5xx or 429, by http method.",
+	}, []string{"method"})
+
+	// ApiCircuitState is the state of the circuit breaker of api server
+	ApiCircuitState = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "circuit_state",
+		Help:      "State of the circuit breaker of BFE api server, 0 for closed, 1 for open and 2 for half-open.",
+	})
+
+	// ApiCircuitOpened counts the times the circuit breaker opened
+	ApiCircuitOpened = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "circuit_opened_total",
+		Help:      "Number of times the circuit breaker of BFE api server opened.",
+	})
+
+	// ApiServerWaitSeconds is the time requests waited for the rate and concurrency limits
+	ApiServerWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "limiter_wait_seconds",
+		Help:      "Time BFE api requests waited for the rate and concurrency limits, by product.",
+		Buckets:   []float64{0, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"product"})
+
+	// ApiServerQueued is the number of requests waiting for the limits
+	ApiServerQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "limiter_queued",
+		Help:      "Number of BFE api requests waiting for the rate and concurrency limits.",
+	})
+
+	// ApiServerInFlight is the number of requests being sent
+	ApiServerInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "in_flight",
+		Help:      "Number of BFE api requests being sent.",
+	})
+
+	// ApiServerHealthy is 1 if the endpoint is regarded as healthy
+	ApiServerHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "api_server",
+		Name:      "healthy",
+		Help:      "Whether the BFE api server endpoint is healthy, 1 for healthy and 0 for failed to connect recently.",
+	}, []string{"endpoint"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		InitialSyncServices,
+		InitialSyncPendingServices,
+		InitialSyncDone,
+		InitialSyncDurationSeconds,
+		InitialSyncTimeout,
+		SelfUpdatesIgnored,
+		PatchesSkipped,
+		EndpointsEvents,
+		EndpointsEventsCoalesced,
+		EndpointsPropagationDelaySeconds,
+		PoolShrinkHeld,
+		PoolCollisions,
+		PoolNotOwned,
+		SharedPoolConflicts,
+		ApiServerRequests,
+		ApiServerFailovers,
+		ApiServerRetries,
+		ApiCircuitState,
+		ApiCircuitOpened,
+		ApiServerHealthy,
+		ApiServerWaitSeconds,
+		ApiServerQueued,
+		ApiServerInFlight,
+	)
+}
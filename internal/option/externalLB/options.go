@@ -14,24 +14,98 @@
 
 package externalLB
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 const (
-	timeout = 3000 // unit ms
+	timeout           = 3000  // unit ms
+	unhealthyCooldown = 10000 // unit ms
+	retries           = 2
+	retryBaseDelay    = 200  // unit ms
+	retryMaxDelay     = 2000 // unit ms
+	circuitThreshold  = 5
+	circuitOpen       = 10000 // unit ms
+	qps               = 20
+	burst             = 40
+	maxInFlight       = 8
+	tlsMinVersion     = "1.2"
+	tokenSecretKey    = "token"
+
+	// ProxyNone disables the proxy to api server
+	ProxyNone = "none"
+
+	BalanceModeFailover   = "failover"
+	BalanceModeRoundRobin = "round-robin"
 )
 
 // Options of external loadbalancer
 type Options struct {
-	ApiServerAddr string
-	Timeout       int //unit ms
-	Token         string
+	ApiServerAddr string // delimited by ',' for multiple addresses
+	Timeout       int    //unit ms
+
+	// token of api server, from exactly one of the sources, the file and the secret are reloaded on change
+	Token       string // visible in process list, prefer TokenFile or TokenSecret
+	TokenFile   string
+	TokenSecret string // <namespace>/<name>[:<key>]
+
+	// retries of a request failed transiently, delays are in ms
+	Retries        int
+	RetryBaseDelay int
+	RetryMaxDelay  int
+
+	// circuit breaker, opens after CircuitThreshold consecutive failures (0 means disabled),
+	// and probes again after CircuitOpenDuration ms
+	CircuitThreshold    int
+	CircuitOpenDuration int
+
+	// limits of requests, queued requests are served in turn across products, 0 means no limit
+	QPS         float64
+	Burst       int
+	MaxInFlight int
+
+	// proxy to api server, empty means from HTTP_PROXY, HTTPS_PROXY and NO_PROXY env
+	Proxy string
+
+	// tls of https addresses, the files are reloaded on change
+	TLSCAFile     string
+	TLSCertFile   string
+	TLSKeyFile    string
+	TLSServerName string
+	TLSMinVersion string
+
+	// how to use multiple addresses
+	BalanceMode       string
+	UnhealthyCooldown int // unit ms
+
+	// timeouts of each kind of operation, unit ms, 0 means Timeout
+	ReadTimeout   int
+	WriteTimeout  int
+	DeleteTimeout int
 }
 
 func NewOptions() *Options {
 	return &Options{
 		ApiServerAddr: "http://172.18.1.200:30001",
-		Token:         "Token f740c3040fed4bd7e97c",
 		Timeout:       timeout,
+
+		Retries:        retries,
+		RetryBaseDelay: retryBaseDelay,
+		RetryMaxDelay:  retryMaxDelay,
+
+		CircuitThreshold:    circuitThreshold,
+		CircuitOpenDuration: circuitOpen,
+
+		QPS:         qps,
+		Burst:       burst,
+		MaxInFlight: maxInFlight,
+
+		TLSMinVersion: tlsMinVersion,
+
+		BalanceMode:       BalanceModeFailover,
+		UnhealthyCooldown: unhealthyCooldown,
 	}
 }
 
@@ -39,10 +113,133 @@ func (opts *Options) Check() error {
 	if opts.ApiServerAddr == "" {
 		return fmt.Errorf("alb api server address is not not specified")
 	}
+	for _, addr := range strings.Split(opts.ApiServerAddr, ",") {
+		if strings.TrimSpace(addr) == "" {
+			return fmt.Errorf("invalid command line argument bfe-api-addr, should not contain empty address")
+		}
+	}
+
+	if opts.BalanceMode != BalanceModeFailover && opts.BalanceMode != BalanceModeRoundRobin {
+		return fmt.Errorf("invalid command line argument bfe-api-balance-mode, should be %s or %s", BalanceModeFailover, BalanceModeRoundRobin)
+	}
+
+	if opts.Retries < 0 {
+		return fmt.Errorf("invalid command line argument bfe-api-retries, should >= 0")
+	}
+
+	if opts.RetryBaseDelay <= 0 || opts.RetryMaxDelay < opts.RetryBaseDelay {
+		return fmt.Errorf("invalid command line argument bfe-api-retry-base-delay or bfe-api-retry-max-delay, should be 0 < base delay <= max delay")
+	}
+
+	if opts.CircuitThreshold < 0 || opts.CircuitOpenDuration <= 0 {
+		return fmt.Errorf("invalid command line argument bfe-api-circuit-threshold or bfe-api-circuit-open-duration, should be threshold >= 0 and duration > 0")
+	}
+
+	if opts.QPS < 0 || opts.MaxInFlight < 0 {
+		return fmt.Errorf("invalid command line argument bfe-api-qps or bfe-api-max-in-flight, should >= 0")
+	}
+
+	if opts.QPS > 0 && opts.Burst < 1 {
+		return fmt.Errorf("invalid command line argument bfe-api-burst, should >= 1")
+	}
+
+	if opts.Proxy != "" && opts.Proxy != ProxyNone {
+		if u, err := url.Parse(opts.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid command line argument bfe-api-proxy, should be a url like http://host:port or %s", ProxyNone)
+		}
+	}
+
+	if (opts.TLSCertFile == "") != (opts.TLSKeyFile == "") {
+		return fmt.Errorf("invalid command line argument bfe-api-tls-cert-file or bfe-api-tls-key-file, should be specified together")
+	}
 
-	if opts.Token == "" {
-		return fmt.Errorf("alb api server token is not not specified")
+	switch opts.TLSMinVersion {
+	case "1.0", "1.1", "1.2", "1.3":
+	default:
+		return fmt.Errorf("invalid command line argument bfe-api-tls-min-version, should be 1.0, 1.1, 1.2 or 1.3")
 	}
 
+	if opts.UnhealthyCooldown < 0 {
+		return fmt.Errorf("invalid command line argument bfe-api-unhealthy-cooldown, should >= 0")
+	}
+
+	if err := opts.checkToken(); err != nil {
+		return err
+	}
+
+	if opts.Timeout <= 0 {
+		return fmt.Errorf("invalid command line argument bfe-api-timeout, should > 0")
+	}
+
+	if opts.ReadTimeout < 0 || opts.WriteTimeout < 0 || opts.DeleteTimeout < 0 {
+		return fmt.Errorf("invalid command line argument bfe-api-read-timeout, bfe-api-write-timeout or bfe-api-delete-timeout, should >= 0")
+	}
+
+	return nil
+}
+
+func (opts *Options) checkToken() error {
+	sources := 0
+	for _, source := range []string{opts.Token, opts.TokenFile, opts.TokenSecret} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources == 0 {
+		return fmt.Errorf("alb api server token is not specified, set bfe-api-token-file, bfe-api-token-secret or bfe-api-token")
+	}
+	if sources > 1 {
+		return fmt.Errorf("invalid command line argument bfe-api-token-file, bfe-api-token-secret or bfe-api-token, should specify only one of them")
+	}
+
+	if opts.TokenSecret != "" {
+		if _, _, _, err := opts.GetTokenSecret(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// GetTokenSecret returns the namespace, name and key of the secret holding the token
+func (opts *Options) GetTokenSecret() (string, string, string, error) {
+	ref, key, _ := strings.Cut(opts.TokenSecret, ":")
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" {
+		return "", "", "", fmt.Errorf("invalid command line argument bfe-api-token-secret, should be <namespace>/<name>[:<key>]")
+	}
+	if key == "" {
+		key = tokenSecretKey
+	}
+	return namespace, name, key, nil
+}
+
+// GetApiServerAddrs returns the addresses of api server
+func (opts *Options) GetApiServerAddrs() []string {
+	addrs := make([]string, 0)
+	for _, addr := range strings.Split(opts.ApiServerAddr, ",") {
+		addrs = append(addrs, strings.TrimSpace(addr))
+	}
+	return addrs
+}
+
+// GetReadTimeout returns the timeout of reading pools, unit ms
+func (opts *Options) GetReadTimeout() int {
+	return opts.timeoutOr(opts.ReadTimeout)
+}
+
+// GetWriteTimeout returns the timeout of creating or updating pools, unit ms
+func (opts *Options) GetWriteTimeout() int {
+	return opts.timeoutOr(opts.WriteTimeout)
+}
+
+// GetDeleteTimeout returns the timeout of deleting pools, unit ms
+func (opts *Options) GetDeleteTimeout() int {
+	return opts.timeoutOr(opts.DeleteTimeout)
+}
+
+func (opts *Options) timeoutOr(timeout int) int {
+	if timeout > 0 {
+		return timeout
+	}
+	return opts.Timeout
+}
@@ -24,16 +24,36 @@ import (
 
 const (
 	ClusterName            = "" //"default"
+	ControllerID           = "bfe-service-controller"
 	MetricsBindAddress     = ":9080"
 	HealthProbeBindAddress = ":9081"
 	PProfAddress           = ""
 	ReconcileRate          = 10
 	ReconcileBucket        = 100
+	EndpointsMaxDelayMs    = 10000
+	ClusterWeight          = 1
+	SharedPoolResyncS      = 60
 
 	ReadinessEndpointName = "/readyz"
 	LivenessEndpointName  = "/healthz"
+	StatuszEndpointName   = "/statusz"
 	UnreadyDuration       = 30
 
+	ApiProbeInterval   = 10
+	ApiProbeModeFail   = "fail"
+	ApiProbeModeReport = "report"
+
+	ApiCircuitModeFail   = "fail"
+	ApiCircuitModeReport = "report"
+
+	ResultModeCondition = "condition"
+	ResultModeConfigmap = "configmap"
+	ResultModeBoth      = "both"
+
+	DeletionPolicyDelete = "delete"
+	DeletionPolicyRetain = "retain"
+	DeletionPolicyClear  = "clear"
+
 	NlbAccessTypeDEP = "DirectEndpoint"
 	NlbAccessTypeNP  = "NodePort"
 	NlbAccessTypeVXL = "VXLan"
@@ -44,13 +64,20 @@ const (
 )
 
 type Options struct {
-	ClusterName string
+	ClusterName  string
+	ControllerID string
+
+	AdoptUnownedPools bool
+	SharedPool        bool
+	SharedPoolResyncS int
+	ClusterWeight     int
 
 	ExternalLB *externalLB.Options
 
 	RetryIntervalUnitForErrS int
 
 	ForceRmFinalizer bool
+	DeletionPolicy   string
 
 	Namespaces       string
 	NamespaceList    []string
@@ -61,9 +88,24 @@ type Options struct {
 	ReadinessEndpointName string
 	UnreadyDuration       int
 	LivenessEndpointName  string
+	StatuszEndpointName   string
 	PProfAddr             string
 	ReconcileRate         int
 	ReconcileBucket       int
+
+	ApiProbeInterval int
+	ApiProbeMode     string
+	ApiCircuitMode   string
+
+	ResultMode string
+
+	EnableServiceBinding bool
+
+	EndpointsDebounceMs int
+	EndpointsMaxDelayMs int
+
+	MaxShrinkRatio   float64
+	MinPoolInstances int
 }
 
 var (
@@ -73,21 +115,31 @@ var (
 func NewOptions() *Options {
 	return &Options{
 		ClusterName:           ClusterName,
+		ControllerID:          ControllerID,
 		Namespaces:            corev1.NamespaceAll,
 		MetricsAddr:           MetricsBindAddress,
 		HealthProbeAddr:       HealthProbeBindAddress,
 		ReadinessEndpointName: ReadinessEndpointName,
 		UnreadyDuration:       UnreadyDuration,
 		LivenessEndpointName:  LivenessEndpointName,
+		StatuszEndpointName:   StatuszEndpointName,
 		PProfAddr:             PProfAddress,
 		ReconcileRate:         ReconcileRate,
 		ReconcileBucket:       ReconcileBucket,
+		ApiProbeInterval:      ApiProbeInterval,
+		ApiProbeMode:          ApiProbeModeFail,
+		ApiCircuitMode:        ApiCircuitModeFail,
+		ResultMode:            ResultModeCondition,
+		EndpointsMaxDelayMs:   EndpointsMaxDelayMs,
+		ClusterWeight:         ClusterWeight,
+		SharedPoolResyncS:     SharedPoolResyncS,
 
 		ExternalLB: externalLB.NewOptions(),
 
 		RetryIntervalUnitForErrS: 15,
 
 		ForceRmFinalizer: false,
+		DeletionPolicy:   DeletionPolicyDelete,
 
 		SkipNilSvcDelete: true,
 	}
@@ -107,6 +159,50 @@ func SetOptions(option *Options) error {
 		return fmt.Errorf("invalid command line argument reconcile-bucket, should > 0")
 	}
 
+	if option.ApiProbeMode != ApiProbeModeFail && option.ApiProbeMode != ApiProbeModeReport {
+		return fmt.Errorf("invalid command line argument bfe-api-probe-mode, should be %s or %s", ApiProbeModeFail, ApiProbeModeReport)
+	}
+
+	if option.ApiCircuitMode != ApiCircuitModeFail && option.ApiCircuitMode != ApiCircuitModeReport {
+		return fmt.Errorf("invalid command line argument bfe-api-circuit-mode, should be %s or %s", ApiCircuitModeFail, ApiCircuitModeReport)
+	}
+
+	if option.ResultMode != ResultModeCondition && option.ResultMode != ResultModeConfigmap && option.ResultMode != ResultModeBoth {
+		return fmt.Errorf("invalid command line argument result-mode, should be %s, %s or %s", ResultModeCondition, ResultModeConfigmap, ResultModeBoth)
+	}
+
+	if option.EndpointsDebounceMs < 0 || option.EndpointsMaxDelayMs < option.EndpointsDebounceMs {
+		return fmt.Errorf("invalid command line argument endpoints-debounce-ms or endpoints-max-delay-ms, should be 0 <= debounce <= max delay")
+	}
+
+	if option.MaxShrinkRatio < 0 || option.MaxShrinkRatio > 1 {
+		return fmt.Errorf("invalid command line argument max-shrink-ratio, should be in [0, 1]")
+	}
+
+	if option.MinPoolInstances < 0 {
+		return fmt.Errorf("invalid command line argument min-pool-instances, should >= 0")
+	}
+
+	if option.ControllerID == "" {
+		return fmt.Errorf("invalid command line argument controller-id, should not be empty")
+	}
+
+	if option.ClusterWeight < 0 || option.ClusterWeight > 100 {
+		return fmt.Errorf("invalid command line argument cluster-weight, should be in [0, 100]")
+	}
+
+	if option.SharedPoolResyncS < 0 {
+		return fmt.Errorf("invalid command line argument shared-pool-resync-sec, should >= 0")
+	}
+
+	if option.SharedPool && option.ClusterName == "" {
+		return fmt.Errorf("invalid command line argument k8s-cluster-name, should not be empty with shared-pool")
+	}
+
+	if !IsValidDeletionPolicy(option.DeletionPolicy) {
+		return fmt.Errorf("invalid command line argument deletion-policy, should be %s, %s or %s", DeletionPolicyDelete, DeletionPolicyRetain, DeletionPolicyClear)
+	}
+
 	if err := option.ExternalLB.Check(); err != nil {
 		return err
 	}
@@ -116,3 +212,8 @@ func SetOptions(option *Options) error {
 
 	return nil
 }
+
+// IsValidDeletionPolicy checks whether policy is one of delete, retain and clear
+func IsValidDeletionPolicy(policy string) bool {
+	return policy == DeletionPolicyDelete || policy == DeletionPolicyRetain || policy == DeletionPolicyClear
+}
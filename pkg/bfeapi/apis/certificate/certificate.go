@@ -0,0 +1,41 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+// CreateParam Request Param of uploading a certificate
+type CreateParam struct {
+	CertName        *string `json:"cert_name"`
+	Description     *string `json:"description,omitempty"`
+	IsDefault       *bool   `json:"is_default,omitempty"`
+	CertFileName    *string `json:"cert_file_name"`
+	CertFileContent *string `json:"cert_file_content"` // PEM
+	KeyFileName     *string `json:"key_file_name"`
+	KeyFileContent  *string `json:"key_file_content"` // PEM
+	ExpiredDate     *string `json:"expired_date,omitempty"`
+}
+
+// DefaultParam Request Param of setting the default certificate
+type DefaultParam struct {
+	CertName *string `json:"cert_name"`
+}
+
+// OneRsp Response of a certificate, the private key is never returned
+type OneRsp struct {
+	CertName     string `json:"cert_name"`
+	Description  string `json:"description"`
+	IsDefault    bool   `json:"is_default"`
+	CertFileName string `json:"cert_file_name"`
+	ExpiredDate  string `json:"expired_date"`
+}
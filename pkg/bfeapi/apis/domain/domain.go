@@ -12,13 +12,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package apis
+package domain
 
-import "encoding/json"
+// CreateParam Request Param of creating a domain of a product
+type CreateParam struct {
+	Name                  *string `json:"name"`
+	UsingAdvancedRedirect *int    `json:"using_advanced_redirect,omitempty"`
+	UsingAdvancedHsts     *int    `json:"using_advanced_hsts,omitempty"`
+}
 
-// Result used for result process
-type Result struct {
-	ErrNum int             `json:"ErrNum"`         // http status code
-	RetMsg string          `json:"ErrMsg"`         // return message if failed
-	Data   json.RawMessage `json:"Data,omitempty"` // return data if succes
+// OneRsp Response of a domain
+type OneRsp struct {
+	Name                  string `json:"name"`
+	UsingAdvancedRedirect int    `json:"using_advanced_redirect"`
+	UsingAdvancedHsts     int    `json:"using_advanced_hsts"`
 }
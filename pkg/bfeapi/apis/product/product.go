@@ -0,0 +1,33 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package product
+
+// UpsertParam Request Param of creating or updating a product, nil fields are not updated
+type UpsertParam struct {
+	Name              *string  `json:"name,omitempty"`
+	Description       *string  `json:"description,omitempty"`
+	MailList          []string `json:"mail_list,omitempty"`
+	ContactPersonList []string `json:"contact_person_list,omitempty"`
+	SmsList           []string `json:"sms_list,omitempty"`
+}
+
+// OneRsp Response of a product
+type OneRsp struct {
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	MailList          []string `json:"mail_list"`
+	ContactPersonList []string `json:"contact_person_list"`
+	SmsList           []string `json:"sms_list"`
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route_rule
+
+// RouteRule route rules of a product, replaced as a whole when updated
+type RouteRule struct {
+	BasicRules   []*BasicRule   `json:"basic_rules"`
+	AdvanceRules []*AdvanceRule `json:"advance_rules"`
+}
+
+// BasicRule forwards requests matching hosts and paths to a cluster
+type BasicRule struct {
+	Description string   `json:"description"`
+	HostNames   []string `json:"host_names"`
+	Paths       []string `json:"paths"`
+	ClusterName string   `json:"cluster_name"`
+}
+
+// AdvanceRule forwards requests matching a condition expression to a cluster,
+// checked after the basic rules in order
+type AdvanceRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+	ClusterName string `json:"cluster_name"`
+}
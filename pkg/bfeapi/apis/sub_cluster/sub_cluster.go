@@ -0,0 +1,29 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sub_cluster
+
+// UpsertParam Request Param of creating or updating a sub-cluster of a product, nil fields are not updated
+type UpsertParam struct {
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	InstancePool *string `json:"instance_pool,omitempty"` // the instance pool serving the sub-cluster
+}
+
+// OneRsp Response of a sub-cluster
+type OneRsp struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	InstancePool string `json:"instance_pool"`
+}
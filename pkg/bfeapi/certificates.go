@@ -0,0 +1,51 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/certificate"
+)
+
+const (
+	defaultCertificate = "default"
+)
+
+func (c *Client) ListCertificate(ctx context.Context) ([]*certificate.OneRsp, error) {
+	rsp := make([]*certificate.OneRsp, 0)
+	if err := c.do(ctx, http.MethodGet, apiPath("certificates"), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CreateCertificate(ctx context.Context, req *certificate.CreateParam) (*certificate.OneRsp, error) {
+	rsp := &certificate.OneRsp{}
+	if err := c.do(ctx, http.MethodPost, apiPath("certificates"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) SetDefaultCertificate(ctx context.Context, name string) error {
+	req := &certificate.DefaultParam{CertName: &name}
+	return c.do(ctx, http.MethodPatch, apiPath("certificates", defaultCertificate), req, nil)
+}
+
+func (c *Client) DeleteCertificate(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, apiPath("certificates", name), nil, nil)
+}
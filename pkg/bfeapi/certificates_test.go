@@ -0,0 +1,78 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/certificate"
+)
+
+func TestCertificate(t *testing.T) {
+	name, certFile, cert, keyFile, key := "example", "example.crt", "CERT", "example.key", "KEY"
+	runCases(t, []apiCase{{
+		name:  "list",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":[{"cert_name":"example","is_default":true,"expired_date":"2027-01-01"}]}`,
+		call: func(ctx context.Context, c *Client) error {
+			list, err := c.ListCertificate(ctx)
+			if err == nil && (len(list) != 1 || !list[0].IsDefault || list[0].ExpiredDate != "2027-01-01") {
+				return fmt.Errorf("unexpected certificates %v", list)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/certificates",
+	}, {
+		name:  "create",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"cert_name":"example"}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.CreateCertificate(ctx, &certificate.CreateParam{
+				CertName:        &name,
+				CertFileName:    &certFile,
+				CertFileContent: &cert,
+				KeyFileName:     &keyFile,
+				KeyFileContent:  &key,
+			})
+			if err == nil && one.CertName != name {
+				return fmt.Errorf("unexpected certificate %v", one)
+			}
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/certificates", body: `"key_file_content":"KEY"`,
+	}, {
+		name: "set default",
+		call: func(ctx context.Context, c *Client) error {
+			return c.SetDefaultCertificate(ctx, name)
+		},
+		method: http.MethodPatch, path: "/open-api/v1/certificates/default", body: `"cert_name":"example"`,
+	}, {
+		name: "delete escaped",
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteCertificate(ctx, "example/2")
+		},
+		method: http.MethodDelete, path: "/open-api/v1/certificates/example%2F2",
+	}, {
+		name:   "list with invalid token",
+		status: http.StatusUnauthorized,
+		reply:  `{"ErrNum":401,"ErrMsg":"invalid token"}`,
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.ListCertificate(ctx)
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/certificates", want: IsUnauthorized,
+	}})
+}
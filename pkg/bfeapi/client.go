@@ -0,0 +1,142 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bfeapi is a client of the BFE api server for products, instance pools, bfe clusters, sub clusters,
+// domains, routes and certificates. It sends every request once; retrying is left to the caller, see IsTransient.
+// Reading responses and classifying errors are shared with the client of the controller, see ReadResult.
+package bfeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	version = "/open-api/v1"
+
+	// DefaultTimeout is the timeout of a request if Options.Timeout is not given
+	DefaultTimeout = 3 * time.Second
+)
+
+// Options configures a Client
+type Options struct {
+	// Addr is the address of the api server, e.g. http://127.0.0.1:8183
+	Addr string
+	// Token is sent as the Authorization header, e.g. "Token xxx"
+	Token string
+	// Timeout of every request, DefaultTimeout if 0
+	Timeout time.Duration
+	// HTTPClient sends the requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+// Client calls the BFE api server
+type Client struct {
+	addr    string
+	token   string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewClient returns a client of the api server given by opts
+func NewClient(opts Options) (*Client, error) {
+	u, err := url.Parse(opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api server address %q: %s", opts.Addr, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid api server address %q, should be http(s)://<host>[:<port>]", opts.Addr)
+	}
+	if opts.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout %s, should >= 0", opts.Timeout)
+	}
+
+	c := &Client{
+		addr:    strings.TrimRight(opts.Addr, "/"),
+		token:   opts.Token,
+		timeout: opts.Timeout,
+		client:  opts.HTTPClient,
+	}
+	if c.timeout == 0 {
+		c.timeout = DefaultTimeout
+	}
+	if c.client == nil {
+		c.client = http.DefaultClient
+	}
+	return c, nil
+}
+
+// apiPath returns the uri of the escaped segments under the api version
+func apiPath(segments ...string) string {
+	var b strings.Builder
+	b.WriteString(version)
+	for _, s := range segments {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(s))
+	}
+	return b.String()
+}
+
+// do sends the request with in as the body, and decodes the data of a successful result into out if given
+func (c *Client) do(ctx context.Context, method, uri string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("fail to marshal request of %s %s: %s", method, uri, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+uri, body)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", c.token)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return NewTransportError(err)
+	}
+	res, err := ReadResult(resp)
+	if err != nil {
+		return err
+	}
+	if err := res.Err(); err != nil {
+		return err
+	}
+	if out == nil || len(res.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(res.Data, out); err != nil {
+		return fmt.Errorf("fail to unmarshal data of %s %s: %s", method, uri, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testToken = "Token test"
+	okReply   = `{"ErrNum":200,"ErrMsg":"OK"}`
+)
+
+// apiServer is a fake api server replying a fixed response, and recording the last request
+type apiServer struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	status int
+	reply  string
+	method string
+	path   string
+	auth   string
+	body   string
+}
+
+func newAPIServer(t *testing.T) (*apiServer, *Client) {
+	s := &apiServer{status: http.StatusOK, reply: okReply}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.method, s.path, s.auth, s.body = r.Method, r.URL.EscapedPath(), r.Header.Get("Authorization"), string(body)
+		w.WriteHeader(s.status)
+		io.WriteString(w, s.reply)
+	}))
+	t.Cleanup(s.Close)
+
+	c, err := NewClient(Options{Addr: s.URL + "/", Token: testToken})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	return s, c
+}
+
+// respond sets the response of the following requests
+func (s *apiServer) respond(status int, reply string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status, s.reply = status, reply
+}
+
+// apiCase is a call of the client, checked by the request it sends and the error it returns
+type apiCase struct {
+	name   string
+	status int    // of the response, 200 if 0
+	reply  string // okReply if empty
+	call   func(ctx context.Context, c *Client) error
+
+	method string
+	path   string
+	body   string           // part of the request body, not checked if empty
+	want   func(error) bool // the expected error, success if nil
+}
+
+// runCases runs every case against a fake api server
+func runCases(t *testing.T, cases []apiCase) {
+	s, c := newAPIServer(t)
+	for _, tc := range cases {
+		status, reply := tc.status, tc.reply
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if reply == "" {
+			reply = okReply
+		}
+		s.respond(status, reply)
+
+		err := tc.call(context.Background(), c)
+		if tc.want == nil && err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+		if tc.want != nil && !tc.want(err) {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+
+		s.lock.Lock()
+		if s.method != tc.method || s.path != tc.path {
+			t.Errorf("%s: request %s %s, want %s %s", tc.name, s.method, s.path, tc.method, tc.path)
+		}
+		if s.auth != testToken {
+			t.Errorf("%s: authorization %q, want %q", tc.name, s.auth, testToken)
+		}
+		if tc.body != "" && !strings.Contains(s.body, tc.body) {
+			t.Errorf("%s: body %s, want %s", tc.name, s.body, tc.body)
+		}
+		s.lock.Unlock()
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	for _, addr := range []string{"", "127.0.0.1:8183", "ftp://127.0.0.1", "http://"} {
+		if _, err := NewClient(Options{Addr: addr}); err == nil {
+			t.Errorf("NewClient(%q) should fail", addr)
+		}
+	}
+	if _, err := NewClient(Options{Addr: "http://127.0.0.1:8183", Timeout: -1}); err == nil {
+		t.Errorf("NewClient with negative timeout should fail")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s, c := newAPIServer(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		status int
+		reply  string
+		want   ErrorType
+	}{
+		{http.StatusNotFound, `{"ErrNum":404,"ErrMsg":"Not Found"}`, ErrorNotFound},
+		{http.StatusOK, `{"ErrNum":404,"ErrMsg":"Not Found"}`, ErrorNotFound},
+		{http.StatusUnprocessableEntity, `{"ErrNum":422,"ErrMsg":"Product Not Exist"}`, ErrorProductNotFound},
+		{http.StatusConflict, `{"ErrNum":409,"ErrMsg":"Already Exist"}`, ErrorConflict},
+		{http.StatusBadRequest, `{"ErrNum":400,"ErrMsg":"name is required"}`, ErrorInvalid},
+		{http.StatusForbidden, `{"ErrNum":403,"ErrMsg":"Forbidden"}`, ErrorUnauthorized},
+		{http.StatusServiceUnavailable, `{"ErrNum":503,"ErrMsg":"busy"}`, ErrorTransient},
+		// status only, e.g. from a proxy in between
+		{http.StatusNotFound, `<html>404 Not Found</html>`, ErrorUnknown},
+		{http.StatusConflict, ``, ErrorUnknown},
+		{http.StatusBadGateway, `<html>502 Bad Gateway</html>`, ErrorTransient},
+		{http.StatusBadGateway, `{"ErrNum":200,"ErrMsg":"OK"}`, ErrorTransient},
+		{http.StatusTooManyRequests, `slow down`, ErrorTransient},
+		{http.StatusOK, `not json`, ErrorUnknown},
+	}
+	for _, tc := range cases {
+		s.respond(tc.status, tc.reply)
+		_, err := c.GetProduct(ctx, "demo")
+		if err == nil || ErrorTypeOf(err) != tc.want {
+			t.Errorf("status %d, reply %q: got %v, want type %s", tc.status, tc.reply, err, tc.want)
+		}
+	}
+}
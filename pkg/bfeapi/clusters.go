@@ -0,0 +1,95 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/bfe_cluster"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/sub_cluster"
+)
+
+func (c *Client) ListBfeCluster(ctx context.Context) ([]*bfe_cluster.OneRsp, error) {
+	rsp := make([]*bfe_cluster.OneRsp, 0)
+	if err := c.do(ctx, http.MethodGet, apiPath("bfe-clusters"), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) GetBfeCluster(ctx context.Context, name string) (*bfe_cluster.OneRsp, error) {
+	rsp := &bfe_cluster.OneRsp{}
+	if err := c.do(ctx, http.MethodGet, apiPath("bfe-clusters", name), nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CreateBfeCluster(ctx context.Context, req *bfe_cluster.UpsertParam) (*bfe_cluster.OneRsp, error) {
+	rsp := &bfe_cluster.OneRsp{}
+	if err := c.do(ctx, http.MethodPost, apiPath("bfe-clusters"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) UpdateBfeCluster(ctx context.Context, name string, req *bfe_cluster.UpsertParam) (*bfe_cluster.OneRsp, error) {
+	rsp := &bfe_cluster.OneRsp{}
+	if err := c.do(ctx, http.MethodPatch, apiPath("bfe-clusters", name), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) DeleteBfeCluster(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, apiPath("bfe-clusters", name), nil, nil)
+}
+
+func (c *Client) ListSubCluster(ctx context.Context, product string) ([]*sub_cluster.OneRsp, error) {
+	rsp := make([]*sub_cluster.OneRsp, 0)
+	if err := c.do(ctx, http.MethodGet, apiPath("products", product, "sub-clusters"), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) GetSubCluster(ctx context.Context, product, name string) (*sub_cluster.OneRsp, error) {
+	rsp := &sub_cluster.OneRsp{}
+	if err := c.do(ctx, http.MethodGet, apiPath("products", product, "sub-clusters", name), nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CreateSubCluster(ctx context.Context, product string, req *sub_cluster.UpsertParam) (*sub_cluster.OneRsp, error) {
+	rsp := &sub_cluster.OneRsp{}
+	if err := c.do(ctx, http.MethodPost, apiPath("products", product, "sub-clusters"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) UpdateSubCluster(ctx context.Context, product, name string, req *sub_cluster.UpsertParam) (*sub_cluster.OneRsp, error) {
+	rsp := &sub_cluster.OneRsp{}
+	if err := c.do(ctx, http.MethodPatch, apiPath("products", product, "sub-clusters", name), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) DeleteSubCluster(ctx context.Context, product, name string) error {
+	return c.do(ctx, http.MethodDelete, apiPath("products", product, "sub-clusters", name), nil, nil)
+}
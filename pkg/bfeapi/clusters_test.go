@@ -0,0 +1,129 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/bfe_cluster"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/sub_cluster"
+)
+
+func TestBfeCluster(t *testing.T) {
+	name, capacity := "gz", int64(200)
+	runCases(t, []apiCase{{
+		name:  "list",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":[{"name":"gz","pool":"bfe.gz","capacity":100,"enabled":true}]}`,
+		call: func(ctx context.Context, c *Client) error {
+			list, err := c.ListBfeCluster(ctx)
+			if err == nil && (len(list) != 1 || list[0].Pool != "bfe.gz" || !list[0].Enabled) {
+				return fmt.Errorf("unexpected bfe clusters %v", list)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/bfe-clusters",
+	}, {
+		name:  "get",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"gz","capacity":100}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.GetBfeCluster(ctx, "gz")
+			if err == nil && one.Capacity != 100 {
+				return fmt.Errorf("unexpected bfe cluster %v", one)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/bfe-clusters/gz",
+	}, {
+		name: "create",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateBfeCluster(ctx, &bfe_cluster.UpsertParam{Name: &name})
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/bfe-clusters", body: `"name":"gz"`,
+	}, {
+		name: "update escaped",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateBfeCluster(ctx, "gz#1", &bfe_cluster.UpsertParam{Capacity: &capacity})
+			return err
+		},
+		method: http.MethodPatch, path: "/open-api/v1/bfe-clusters/gz%231", body: `"capacity":200`,
+	}, {
+		name: "delete",
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteBfeCluster(ctx, name)
+		},
+		method: http.MethodDelete, path: "/open-api/v1/bfe-clusters/gz",
+	}})
+}
+
+func TestSubCluster(t *testing.T) {
+	name, pool := "web", "demo.web2"
+	runCases(t, []apiCase{{
+		name:  "list",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":[{"name":"web","instance_pool":"demo.web"}]}`,
+		call: func(ctx context.Context, c *Client) error {
+			list, err := c.ListSubCluster(ctx, "demo")
+			if err == nil && (len(list) != 1 || list[0].InstancePool != "demo.web") {
+				return fmt.Errorf("unexpected sub clusters %v", list)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo/sub-clusters",
+	}, {
+		// every segment is escaped
+		name:  "get escaped",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"web/v2"}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.GetSubCluster(ctx, "de mo", "web/v2")
+			if err == nil && one.Name != "web/v2" {
+				return fmt.Errorf("unexpected sub cluster %v", one)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/de%20mo/sub-clusters/web%2Fv2",
+	}, {
+		name: "create",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateSubCluster(ctx, "demo", &sub_cluster.UpsertParam{Name: &name, InstancePool: &pool})
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/products/demo/sub-clusters", body: `"instance_pool":"demo.web2"`,
+	}, {
+		name: "update",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateSubCluster(ctx, "demo", name, &sub_cluster.UpsertParam{InstancePool: &pool})
+			return err
+		},
+		method: http.MethodPatch, path: "/open-api/v1/products/demo/sub-clusters/web", body: `"instance_pool":"demo.web2"`,
+	}, {
+		name: "delete escaped",
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteSubCluster(ctx, "demo", "../web")
+		},
+		method: http.MethodDelete, path: "/open-api/v1/products/demo/sub-clusters/..%2Fweb",
+	}, {
+		name:   "create existing",
+		status: http.StatusConflict,
+		reply:  `{"ErrNum":409,"ErrMsg":"sub cluster already exists"}`,
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateSubCluster(ctx, "demo", &sub_cluster.UpsertParam{Name: &name})
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/products/demo/sub-clusters", want: IsConflict,
+	}})
+}
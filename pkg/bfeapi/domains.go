@@ -0,0 +1,59 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/domain"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/route_rule"
+)
+
+func (c *Client) ListDomain(ctx context.Context, product string) ([]*domain.OneRsp, error) {
+	rsp := make([]*domain.OneRsp, 0)
+	if err := c.do(ctx, http.MethodGet, apiPath("products", product, "domains"), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CreateDomain(ctx context.Context, product string, req *domain.CreateParam) (*domain.OneRsp, error) {
+	rsp := &domain.OneRsp{}
+	if err := c.do(ctx, http.MethodPost, apiPath("products", product, "domains"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) DeleteDomain(ctx context.Context, product, name string) error {
+	return c.do(ctx, http.MethodDelete, apiPath("products", product, "domains", name), nil, nil)
+}
+
+func (c *Client) GetRouteRule(ctx context.Context, product string) (*route_rule.RouteRule, error) {
+	rsp := &route_rule.RouteRule{}
+	if err := c.do(ctx, http.MethodGet, apiPath("products", product, "routes"), nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) UpdateRouteRule(ctx context.Context, product string, req *route_rule.RouteRule) (*route_rule.RouteRule, error) {
+	rsp := &route_rule.RouteRule{}
+	if err := c.do(ctx, http.MethodPatch, apiPath("products", product, "routes"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/domain"
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/route_rule"
+)
+
+func TestDomain(t *testing.T) {
+	name := "*.example.com"
+	runCases(t, []apiCase{{
+		name:  "list",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":[{"name":"www.example.com","using_advanced_hsts":1}]}`,
+		call: func(ctx context.Context, c *Client) error {
+			list, err := c.ListDomain(ctx, "demo")
+			if err == nil && (len(list) != 1 || list[0].UsingAdvancedHsts != 1) {
+				return fmt.Errorf("unexpected domains %v", list)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo/domains",
+	}, {
+		name:  "create",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"*.example.com"}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.CreateDomain(ctx, "demo", &domain.CreateParam{Name: &name})
+			if err == nil && one.Name != name {
+				return fmt.Errorf("unexpected domain %v", one)
+			}
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/products/demo/domains", body: `"name":"*.example.com"`,
+	}, {
+		// every segment is escaped
+		name: "delete escaped",
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteDomain(ctx, "de/mo", "www.example.com?x")
+		},
+		method: http.MethodDelete, path: "/open-api/v1/products/de%2Fmo/domains/www.example.com%3Fx",
+	}, {
+		name:   "delete nonexistent",
+		status: http.StatusNotFound,
+		reply:  `{"ErrNum":404,"ErrMsg":"domain not found"}`,
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteDomain(ctx, "demo", "www.example.com")
+		},
+		method: http.MethodDelete, path: "/open-api/v1/products/demo/domains/www.example.com", want: IsNotFound,
+	}})
+}
+
+func TestRouteRule(t *testing.T) {
+	rule := &route_rule.RouteRule{
+		AdvanceRules: []*route_rule.AdvanceRule{{Name: "api", Expression: `req_path_prefix_in("/api", false)`, ClusterName: "api"}},
+	}
+	runCases(t, []apiCase{{
+		name:  "get",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"basic_rules":[{"host_names":["www.example.com"],"paths":["/"],"cluster_name":"web"}],"advance_rules":[]}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.GetRouteRule(ctx, "demo")
+			if err == nil && (len(one.BasicRules) != 1 || one.BasicRules[0].ClusterName != "web") {
+				return fmt.Errorf("unexpected route rule %v", one)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo/routes",
+	}, {
+		name: "update",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateRouteRule(ctx, "demo", rule)
+			return err
+		},
+		method: http.MethodPatch, path: "/open-api/v1/products/demo/routes", body: `"cluster_name":"api"`,
+	}, {
+		name:   "update to nonexistent cluster",
+		status: http.StatusUnprocessableEntity,
+		reply:  `{"ErrNum":422,"ErrMsg":"cluster api not exist"}`,
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateRouteRule(ctx, "demo", rule)
+			return err
+		},
+		method: http.MethodPatch, path: "/open-api/v1/products/demo/routes", want: IsInvalid,
+	}})
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorType is the kind of a failed request
+type ErrorType string
+
+const (
+	ErrorNotFound        ErrorType = "NotFound"        // the resource does not exist
+	ErrorProductNotFound ErrorType = "ProductNotFound" // the product does not exist
+	ErrorConflict        ErrorType = "Conflict"        // the resource already exists or is being changed
+	ErrorInvalid         ErrorType = "Invalid"         // the request is rejected by validation
+	ErrorUnauthorized    ErrorType = "Unauthorized"    // the token is invalid or not permitted
+	ErrorTransient       ErrorType = "Transient"       // network error, timeout, throttling or server error, may succeed later
+	ErrorUnknown         ErrorType = "Unknown"
+)
+
+// Error is the error of a request, Code is ErrNum of the result, the http status if there is no
+// result, or -1 if there is no response
+type Error struct {
+	Type    ErrorType
+	Code    int
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bfe api error, type:%s, code:%d, msg:%s", e.Type, e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorTypeOf returns the type of err, ErrorUnknown if it is not an Error
+func ErrorTypeOf(err error) ErrorType {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Type
+	}
+	return ErrorUnknown
+}
+
+// IsNotFound checks whether err is caused by a nonexistent resource
+func IsNotFound(err error) bool {
+	return ErrorTypeOf(err) == ErrorNotFound
+}
+
+// IsProductNotFound checks whether err is caused by a nonexistent product
+func IsProductNotFound(err error) bool {
+	return ErrorTypeOf(err) == ErrorProductNotFound
+}
+
+// IsConflict checks whether err is caused by an existing or changing resource
+func IsConflict(err error) bool {
+	return ErrorTypeOf(err) == ErrorConflict
+}
+
+// IsInvalid checks whether err is caused by an invalid request
+func IsInvalid(err error) bool {
+	return ErrorTypeOf(err) == ErrorInvalid
+}
+
+// IsUnauthorized checks whether err is caused by the token
+func IsUnauthorized(err error) bool {
+	return ErrorTypeOf(err) == ErrorUnauthorized
+}
+
+// IsTransient checks whether err may go away by retrying
+func IsTransient(err error) bool {
+	return ErrorTypeOf(err) == ErrorTransient
+}
+
+// errorTypes maps ErrNum of a result to its type. The api server takes ErrNum from http status codes,
+// codes not listed here are classified as the bare status by ClassifyStatus.
+var errorTypes = map[int]ErrorType{
+	http.StatusBadRequest:          ErrorInvalid,
+	http.StatusUnauthorized:        ErrorUnauthorized,
+	http.StatusForbidden:           ErrorUnauthorized,
+	http.StatusNotFound:            ErrorNotFound,
+	http.StatusConflict:            ErrorConflict,
+	http.StatusUnprocessableEntity: ErrorInvalid,
+}
+
+// productNotExist is the ErrMsg of a missing product. The api server has no ErrNum of its own for it:
+// looking up the product of a request fails with its record-not-exist error "Product Not Exist",
+// returned with ErrNum 404, or 422 by versions validating the product as a request parameter.
+// It is the only message relied on.
+const productNotExist = "Product Not Exist"
+
+// ClassifyResult returns the type of a result with ErrNum code and ErrMsg message, code is not 200
+func ClassifyResult(code int, message string) ErrorType {
+	if (code == http.StatusNotFound || code == http.StatusUnprocessableEntity) &&
+		strings.Contains(message, productNotExist) {
+		return ErrorProductNotFound
+	}
+	if t, ok := errorTypes[code]; ok {
+		return t
+	}
+	return ClassifyStatus(code)
+}
+
+// ClassifyStatus returns the type of a response without a result. Only the status is known,
+// which may come from a proxy in between, so it is never taken as a missing or existing resource.
+func ClassifyStatus(status int) ErrorType {
+	if status == http.StatusRequestTimeout || status == http.StatusTooManyRequests ||
+		status >= http.StatusInternalServerError {
+		return ErrorTransient
+	}
+	return ErrorUnknown
+}
+
+// newStatusError returns the error of a response without a result
+func newStatusError(status int, message string) error {
+	return &Error{Type: ClassifyStatus(status), Code: status, Message: message}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassifyResult(t *testing.T) {
+	cases := []struct {
+		code    int
+		message string
+		want    ErrorType
+	}{
+		{http.StatusNotFound, "Product Not Exist", ErrorProductNotFound},
+		{http.StatusUnprocessableEntity, "Product Not Exist", ErrorProductNotFound},
+		{http.StatusNotFound, "Pool Not Exist", ErrorNotFound},
+		{http.StatusUnprocessableEntity, "instance port out of range", ErrorInvalid},
+		{http.StatusBadRequest, "name is required", ErrorInvalid},
+		{http.StatusConflict, "Pool Already Exist", ErrorConflict},
+		{http.StatusForbidden, "Product Not Exist", ErrorUnauthorized},
+		{http.StatusInternalServerError, "Product Not Exist", ErrorTransient},
+		{http.StatusTooManyRequests, "", ErrorTransient},
+		{http.StatusTeapot, "", ErrorUnknown},
+	}
+	for _, tc := range cases {
+		if got := ClassifyResult(tc.code, tc.message); got != tc.want {
+			t.Errorf("ClassifyResult(%d, %q) = %s, want %s", tc.code, tc.message, got, tc.want)
+		}
+	}
+}
+
+func TestClassifyStatus(t *testing.T) {
+	cases := []struct {
+		status int
+		want   ErrorType
+	}{
+		// a bare status may come from a proxy, it tells nothing of the resource
+		{http.StatusNotFound, ErrorUnknown},
+		{http.StatusConflict, ErrorUnknown},
+		{http.StatusBadRequest, ErrorUnknown},
+		{http.StatusRequestTimeout, ErrorTransient},
+		{http.StatusBadGateway, ErrorTransient},
+	}
+	for _, tc := range cases {
+		if got := ClassifyStatus(tc.status); got != tc.want {
+			t.Errorf("ClassifyStatus(%d) = %s, want %s", tc.status, got, tc.want)
+		}
+	}
+}
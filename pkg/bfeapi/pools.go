@@ -0,0 +1,59 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
+)
+
+func (c *Client) ListProductPool(ctx context.Context, product string) ([]string, error) {
+	rsp := make([]string, 0)
+	if err := c.do(ctx, http.MethodGet, apiPath("products", product, "instance-pools"), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) GetProductPool(ctx context.Context, product, name string) (*product_pool.OneRsp, error) {
+	rsp := &product_pool.OneRsp{}
+	if err := c.do(ctx, http.MethodGet, apiPath("products", product, "instance-pools", name), nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CreateProductPool(ctx context.Context, product string, req *product_pool.UpsertParam) (*product_pool.OneRsp, error) {
+	rsp := &product_pool.OneRsp{}
+	if err := c.do(ctx, http.MethodPost, apiPath("products", product, "instance-pools"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// UpdateProductPool replaces the instances of the pool with req.Instances
+func (c *Client) UpdateProductPool(ctx context.Context, product string, req *product_pool.UpsertParam) (*product_pool.OneRsp, error) {
+	rsp := &product_pool.OneRsp{}
+	if err := c.do(ctx, http.MethodPatch, apiPath("products", product, "instance-pools", *req.Name), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) DeleteProductPool(ctx context.Context, product, name string) error {
+	return c.do(ctx, http.MethodDelete, apiPath("products", product, "instance-pools", name), nil, nil)
+}
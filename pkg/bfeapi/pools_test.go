@@ -0,0 +1,87 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product_pool"
+)
+
+func TestProductPool(t *testing.T) {
+	pool := "demo.web"
+	param := &product_pool.UpsertParam{
+		Name: &pool,
+		Instances: []*product_pool.Instance{{
+			Hostname: "web-0", IP: "10.0.0.1", Weight: 1,
+			Ports: map[string]int{"Default": 80}, Tags: map[string]string{"cluster": "a"},
+		}},
+	}
+	runCases(t, []apiCase{{
+		name:  "list",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":["demo.web","demo.api"]}`,
+		call: func(ctx context.Context, c *Client) error {
+			list, err := c.ListProductPool(ctx, "demo")
+			if err == nil && (len(list) != 2 || list[1] != "demo.api") {
+				return fmt.Errorf("unexpected pools %v", list)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo/instance-pools",
+	}, {
+		name:  "get",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"demo.web","instances":[{"hostname":"web-0","ip":"10.0.0.1","ports":{"Default":80}}]}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.GetProductPool(ctx, "demo", pool)
+			if err == nil && (len(one.Instances) != 1 || one.Instances[0].Ports["Default"] != 80) {
+				return fmt.Errorf("unexpected pool %v", one)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo/instance-pools/demo.web",
+	}, {
+		name: "create",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateProductPool(ctx, "demo", param)
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/products/demo/instance-pools", body: `"ip":"10.0.0.1"`,
+	}, {
+		name: "update",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateProductPool(ctx, "demo", param)
+			return err
+		},
+		method: http.MethodPatch, path: "/open-api/v1/products/demo/instance-pools/demo.web", body: `"tags":{"cluster":"a"}`,
+	}, {
+		name: "delete escaped",
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteProductPool(ctx, "demo", "../web")
+		},
+		method: http.MethodDelete, path: "/open-api/v1/products/demo/instance-pools/..%2Fweb",
+	}, {
+		name:   "get nonexistent",
+		status: http.StatusNotFound,
+		reply:  `{"ErrNum":404,"ErrMsg":"Pool Not Exist"}`,
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.GetProductPool(ctx, "demo", pool)
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo/instance-pools/demo.web", want: IsNotFound,
+	}})
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product"
+)
+
+func (c *Client) ListProduct(ctx context.Context) ([]*product.OneRsp, error) {
+	rsp := make([]*product.OneRsp, 0)
+	if err := c.do(ctx, http.MethodGet, apiPath("products"), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) GetProduct(ctx context.Context, name string) (*product.OneRsp, error) {
+	rsp := &product.OneRsp{}
+	if err := c.do(ctx, http.MethodGet, apiPath("products", name), nil, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CreateProduct(ctx context.Context, req *product.UpsertParam) (*product.OneRsp, error) {
+	rsp := &product.OneRsp{}
+	if err := c.do(ctx, http.MethodPost, apiPath("products"), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) UpdateProduct(ctx context.Context, name string, req *product.UpsertParam) (*product.OneRsp, error) {
+	rsp := &product.OneRsp{}
+	if err := c.do(ctx, http.MethodPatch, apiPath("products", name), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) DeleteProduct(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, apiPath("products", name), nil, nil)
+}
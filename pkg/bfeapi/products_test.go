@@ -0,0 +1,80 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bfenetworks/service-controller/pkg/bfeapi/apis/product"
+)
+
+func TestProduct(t *testing.T) {
+	name, desc := "demo", "new"
+	runCases(t, []apiCase{{
+		name:  "list",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":[{"name":"demo"},{"name":"a b"}]}`,
+		call: func(ctx context.Context, c *Client) error {
+			list, err := c.ListProduct(ctx)
+			if err == nil && (len(list) != 2 || list[1].Name != "a b") {
+				return fmt.Errorf("unexpected products %v", list)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products",
+	}, {
+		name:  "get escaped",
+		reply: `{"ErrNum":200,"ErrMsg":"OK","Data":{"name":"a/b","description":"demo"}}`,
+		call: func(ctx context.Context, c *Client) error {
+			one, err := c.GetProduct(ctx, "a/b")
+			if err == nil && (one.Name != "a/b" || one.Description != "demo") {
+				return fmt.Errorf("unexpected product %v", one)
+			}
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/a%2Fb",
+	}, {
+		name: "create",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateProduct(ctx, &product.UpsertParam{Name: &name})
+			return err
+		},
+		method: http.MethodPost, path: "/open-api/v1/products", body: `"name":"demo"`,
+	}, {
+		name: "update",
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateProduct(ctx, name, &product.UpsertParam{Description: &desc})
+			return err
+		},
+		method: http.MethodPatch, path: "/open-api/v1/products/demo", body: `"description":"new"`,
+	}, {
+		name: "delete",
+		call: func(ctx context.Context, c *Client) error {
+			return c.DeleteProduct(ctx, name)
+		},
+		method: http.MethodDelete, path: "/open-api/v1/products/demo",
+	}, {
+		name:   "get nonexistent",
+		status: http.StatusNotFound,
+		reply:  `{"ErrNum":404,"ErrMsg":"Product Not Exist"}`,
+		call: func(ctx context.Context, c *Client) error {
+			_, err := c.GetProduct(ctx, name)
+			return err
+		},
+		method: http.MethodGet, path: "/open-api/v1/products/demo", want: IsProductNotFound,
+	}})
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// bounds of reading response bodies, in bytes
+	maxResponseBody = 32 << 20
+	maxDrainBody    = 64 << 10
+	maxBodySnippet  = 256
+)
+
+// Result is the envelope of every response of the api server
+type Result struct {
+	ErrNum int             `json:"ErrNum"`         // http status code
+	ErrMsg string          `json:"ErrMsg"`         // message if failed
+	Data   json.RawMessage `json:"Data,omitempty"` // data if succeeded
+}
+
+// Err returns the Error of a result whose ErrNum is not 200, nil otherwise
+func (r *Result) Err() error {
+	if r.ErrNum == http.StatusOK {
+		return nil
+	}
+	return &Error{Type: ClassifyResult(r.ErrNum, r.ErrMsg), Code: r.ErrNum, Message: r.ErrMsg}
+}
+
+// ReadResult reads the result of the api server from resp, and closes the body. The result is returned
+// whatever its ErrNum, see Result.Err. A response without a valid result, e.g. the error page of a proxy
+// in between, fails with an Error classified by its status only.
+func ReadResult(resp *http.Response) (*Result, error) {
+	defer closeBody(resp.Body)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	if err != nil {
+		return nil, NewTransportError(fmt.Errorf("fail to read response body: %s", err))
+	}
+	if len(body) > maxResponseBody {
+		return nil, newStatusError(resp.StatusCode,
+			fmt.Sprintf("response body exceeds %d bytes, status:%s", maxResponseBody, resp.Status))
+	}
+
+	res := &Result{}
+	err = json.Unmarshal(body, res)
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if err != nil || res.ErrNum == 0 || (!success && res.ErrNum == http.StatusOK) {
+		return nil, newStatusError(resp.StatusCode,
+			fmt.Sprintf("unexpected response, status:%s, body:%s", resp.Status, bodySnippet(body)))
+	}
+	return res, nil
+}
+
+// NewTransportError returns the Error of a request failed without a response
+func NewTransportError(err error) error {
+	return &Error{Type: ErrorTransient, Code: -1, Message: err.Error(), Err: err}
+}
+
+// closeBody drains a bounded part of the body before closing it, so the connection can be reused
+func closeBody(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, maxDrainBody))
+	body.Close()
+}
+
+// bodySnippet returns the head of a response body for error messages
+func bodySnippet(body []byte) string {
+	if len(body) > maxBodySnippet {
+		return string(body[:maxBodySnippet]) + "..."
+	}
+	return string(body)
+}
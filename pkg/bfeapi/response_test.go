@@ -0,0 +1,74 @@
+// Copyright (c) 2025 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfeapi
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// countingBody is a response body recording how much is read and whether it is closed
+type countingBody struct {
+	io.Reader
+	read   int
+	closed bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func newResponse(status int, body io.Reader) (*http.Response, *countingBody) {
+	b := &countingBody{Reader: body}
+	return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: b}, b
+}
+
+func TestReadResult(t *testing.T) {
+	resp, body := newResponse(http.StatusOK, strings.NewReader(`{"ErrNum":404,"ErrMsg":"Pool Not Exist"}`))
+	res, err := ReadResult(resp)
+	if err != nil || res.ErrNum != http.StatusNotFound || !IsNotFound(res.Err()) {
+		t.Errorf("result error should be returned as result, got %v, %v", res, err)
+	}
+	if !body.closed {
+		t.Errorf("body not closed")
+	}
+
+	resp, _ = newResponse(http.StatusBadGateway, strings.NewReader("<html>"+strings.Repeat("x", 1000)+"</html>"))
+	_, err = ReadResult(resp)
+	if msg := err.Error(); !strings.Contains(msg, "Bad Gateway") || !strings.Contains(msg, "<html>xxx") ||
+		len(msg) > 2*maxBodySnippet {
+		t.Errorf("error of html page should carry the status and the head of the body, got %s", msg)
+	}
+
+	// an oversized body is rejected, and only a bounded part of the rest is drained
+	resp, body = newResponse(http.StatusOK, io.MultiReader(strings.NewReader(`{"ErrNum":200,"Data":"`),
+		strings.NewReader(strings.Repeat("x", 2*maxResponseBody))))
+	_, err = ReadResult(resp)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") || IsTransient(err) {
+		t.Errorf("response body over %d bytes should be rejected as Unknown, got %v", maxResponseBody, err)
+	}
+	if body.read > maxResponseBody+1+maxDrainBody || !body.closed {
+		t.Errorf("%d bytes read of an oversized body, closed %v", body.read, body.closed)
+	}
+}